@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"time"
@@ -22,6 +23,20 @@ type response struct {
 
 var iAmDone = make(chan bool)
 
+// Output options
+var (
+	rowsToShow   int  // Number of rows shown under each statement
+	showDescribe bool // Show the columns of result sets
+	batchToShow  int  // Number of bind rows shown under array DML
+)
+
+// Filters on end-to-end attributes, shell patterns like ORDERS*
+var (
+	moduleFilter   string
+	actionFilter   string
+	clientIdFilter string
+)
+
 func main() {
 	flag.Usage = func() {
 		fmt.Println("Display all queries contained in trc files.")
@@ -31,6 +46,15 @@ func main() {
 	tsFormat := flag.String("tsFormat", "DD-MON-YYYY HH:MI:SS:FF3", "Timestamp format, oracle's way.")
 	pAfter := flag.String("after", "", "Filter packets exchanged after this date. In same format as tsFormat parameter.")
 	pSortByDate := flag.Bool("date-order", false, "Sort output by date")
+	pBySession := flag.Bool("sessions", false, "Group output by database session")
+	pByTransaction := flag.Bool("transactions", false, "Group output by transaction, with its outcome: committed, rolled back or not ended")
+	pFailed := flag.Bool("failed", false, "Show only statements that failed, with their ORA error")
+	flag.IntVar(&rowsToShow, "rows", 0, "Show the first rows returned by each statement")
+	flag.StringVar(&moduleFilter, "module", "", "Show only statements run with a MODULE matching this pattern, like 'ORDERS*'")
+	flag.StringVar(&actionFilter, "action", "", "Show only statements run with an ACTION matching this pattern")
+	flag.StringVar(&clientIdFilter, "client-id", "", "Show only statements run with a CLIENT_IDENTIFIER matching this pattern")
+	flag.IntVar(&batchToShow, "batch", 3, "Show the first rows of values bound by array DML, with the rows processed by each one when known")
+	flag.BoolVar(&showDescribe, "describe", false, "Show the columns of result sets: name, type and nullability")
 
 	flag.Parse()
 
@@ -57,9 +81,14 @@ func main() {
 	}
 
 	rChan := make(chan response)
-	if *pSortByDate {
+	switch {
+	case *pByTransaction:
+		go transactionOutput(rChan)
+	case *pBySession:
+		go sessionOutput(rChan)
+	case *pSortByDate:
 		go dateSortedOutput(rChan)
-	} else {
+	default:
 		go directOutput(rChan)
 	}
 
@@ -71,7 +100,7 @@ func main() {
 		}
 		for _, fn := range fns {
 			fmt.Println(fn)
-			err = parseFile(fn, timeParser, tAfter, rChan)
+			err = parseFile(fn, timeParser, tAfter, *pFailed, rChan)
 			if err != nil {
 				fmt.Fprintln(os.Stderr, err)
 			}
@@ -81,7 +110,7 @@ func main() {
 	<-iAmDone
 }
 
-func parseFile(fn string, timeParser ts.TimeParserFn, tAfter time.Time, r chan response) error {
+func parseFile(fn string, timeParser ts.TimeParserFn, tAfter time.Time, failedOnly bool, r chan response) error {
 	f, err := os.Open(fn)
 	if err != nil {
 		fmt.Println(err)
@@ -99,6 +128,12 @@ func parseFile(fn string, timeParser ts.TimeParserFn, tAfter time.Time, r chan r
 			}
 			break
 		}
+		if failedOnly && q.Err == nil {
+			continue
+		}
+		if !matchEndToEnd(q.EndToEnd) {
+			continue
+		}
 		var ts time.Time
 		if len(q.Packet.TS) > 0 {
 			ts, err = timeParser(q.Packet.TS)
@@ -118,11 +153,28 @@ func parseFile(fn string, timeParser ts.TimeParserFn, tAfter time.Time, r chan r
 	return nil
 }
 
+// matchEndToEnd checks the end-to-end attributes of the statement against the filters
+func matchEndToEnd(e queries.EndToEnd) bool {
+	for _, f := range []struct{ pattern, value string }{
+		{moduleFilter, e.Module},
+		{actionFilter, e.Action},
+		{clientIdFilter, e.ClientId},
+	} {
+		if f.pattern == "" {
+			continue
+		}
+		if ok, _ := path.Match(f.pattern, f.value); !ok {
+			return false
+		}
+	}
+	return true
+}
+
 func directOutput(ch chan response) {
 	for r := range ch {
 		q, err := r.q, r.err
 		if q != nil {
-			fmt.Fprintln(os.Stdout, q.String())
+			printQuery(q)
 		}
 		if err != nil {
 			fmt.Println(os.Stderr, err)
@@ -131,6 +183,24 @@ func directOutput(ch chan response) {
 	close(iAmDone)
 }
 
+// printQuery prints the query, followed by its columns, its first bind rows and its first rows when asked
+func printQuery(q *queries.Query) {
+	fmt.Fprintln(os.Stdout, q.String())
+	if showDescribe && q.Describe != nil {
+		fmt.Fprintln(os.Stdout, q.Describe.String())
+	}
+	if batchToShow > 0 {
+		if t := q.Batch(batchToShow); t != "" {
+			fmt.Fprintln(os.Stdout, t)
+		}
+	}
+	if rowsToShow > 0 {
+		if t := q.Table(rowsToShow); t != "" {
+			fmt.Fprintln(os.Stdout, t)
+		}
+	}
+}
+
 type responseByDate []response
 
 func (r responseByDate) Len() int           { return len(r) }
@@ -147,7 +217,62 @@ func dateSortedOutput(ch chan response) {
 	}
 	sort.Sort(responseByDate(l))
 	for _, r := range l {
-		fmt.Println(r.q.String())
+		printQuery(r.q)
+	}
+	close(iAmDone)
+}
+
+func sessionOutput(ch chan response) {
+	sessions := []*queries.Session{}
+	bySession := map[*queries.Session][]*queries.Query{}
+
+	for r := range ch {
+		if r.q == nil {
+			continue
+		}
+		if _, ok := bySession[r.q.Session]; !ok {
+			sessions = append(sessions, r.q.Session)
+		}
+		bySession[r.q.Session] = append(bySession[r.q.Session], r.q)
+	}
+	for _, s := range sessions {
+		if s != nil {
+			fmt.Println(s.String())
+			fmt.Println()
+		}
+		for _, q := range bySession[s] {
+			printQuery(q)
+		}
+	}
+	close(iAmDone)
+}
+
+func transactionOutput(ch chan response) {
+	transactions := []*queries.Transaction{}
+	byTransaction := map[*queries.Transaction][]*queries.Query{}
+
+	for r := range ch {
+		if r.q == nil {
+			continue
+		}
+		if _, ok := byTransaction[r.q.Transaction]; !ok {
+			transactions = append(transactions, r.q.Transaction)
+		}
+		byTransaction[r.q.Transaction] = append(byTransaction[r.q.Transaction], r.q)
+	}
+	for _, t := range transactions {
+		if t != nil {
+			fmt.Println(t.String())
+			fmt.Println()
+		}
+		// Queries still fetching rows are received after the following ones
+		l := byTransaction[t]
+		sort.SliceStable(l, func(i, j int) bool {
+			return l[i].Packet.Line < l[j].Packet.Line
+		})
+		for _, q := range l {
+			printQuery(q)
+		}
 	}
 	close(iAmDone)
 }
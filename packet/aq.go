@@ -0,0 +1,376 @@
+package packet
+
+import (
+	"bytes"
+	"io"
+)
+
+// Length of AQ message ids
+const AQMessageIdLength = 16
+
+// TOIDs of the payload types that aren't object types
+var (
+	AQRawTOID  = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x17}
+	AQJsonTOID = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x47}
+)
+
+// Dequeue modes
+const (
+	AQBrowse       uint32 = 1
+	AQLocked       uint32 = 2
+	AQRemove       uint32 = 3
+	AQRemoveNoData uint32 = 4
+)
+
+// AQMessageProperties are the properties of an AQ message, sent with the enqueue and received with the dequeue
+type AQMessageProperties struct {
+	Priority       int32
+	Delay          int32 // Seconds before the message can be dequeued
+	Expiration     int32 // Seconds the message can be dequeued once available, -1 for ever
+	Correlation    string
+	Attempts       int32 // Dequeue attempts
+	ExceptionQueue string
+	State          int32 // Ready, waiting, processed or expired
+}
+
+// AQEnqueue is the client's OAQEQ call, it enqueues a message
+type AQEnqueue struct {
+	Sequence    uint8
+	Queue       string
+	Properties  AQMessageProperties
+	Visibility  uint32
+	PayloadType []byte // TOID of the payload type
+	Payload     []byte // Payload image, as sent after the TOID
+}
+
+func (m AQEnqueue) Code() TTCCode { return TTIFUN }
+
+// Call gives the enqueue function call, without its body
+func (m AQEnqueue) Call() FunctionCall {
+	return FunctionCall{Function: OAQEQ, Sequence: m.Sequence}
+}
+
+// readAQEnqueue decodes the enqueue call, its layout depends on the TTC version
+func (c TTCContext) readAQEnqueue(buff *bytes.Buffer, call FunctionCall) (AQEnqueue, error) {
+	var err error
+	m := AQEnqueue{Sequence: call.Sequence}
+
+	var queueLen, toidLen uint32
+	queueLen, err = readPointerLength(buff)
+	if err != nil {
+		return m, err
+	}
+	m.Properties, err = c.readAQMessageProperties(buff)
+	if err != nil {
+		return m, err
+	}
+	err = SkipUInts(buff, 1, 4) // Recipients
+	if err != nil {
+		return m, err
+	}
+	m.Visibility, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	err = SkipUInts(buff, 1, 4, 4) // Relative message id and sequence deviation
+	if err != nil {
+		return m, err
+	}
+	toidLen, err = readPointerLength(buff)
+	if err != nil {
+		return m, err
+	}
+	// Payload version, payload pointer, returned message id, flags,
+	// extensions, source and max sequence numbers, output ack length
+	err = SkipUInts(buff, 2, 1, 1, 4, 4, 1, 4, 1, 4, 1, 4, 1, 4, 1)
+	if err != nil {
+		return m, err
+	}
+	// Correlation, sender name and address in the call options, charsets
+	err = SkipUInts(buff, 1, 4, 1, 4, 1, 4, 1, 1)
+	if err != nil {
+		return m, err
+	}
+	if c.TTCVersion >= 16 {
+		err = SkipUInts(buff, 1) // JSON payload pointer
+		if err != nil {
+			return m, err
+		}
+	}
+
+	m.Queue, err = readAQString(buff, queueLen)
+	if err != nil {
+		return m, err
+	}
+	m.PayloadType, err = readLocator(buff, int(toidLen))
+	if err != nil {
+		return m, err
+	}
+	m.Payload = buff.Next(buff.Len())
+	return m, nil
+}
+
+// AQDequeue is the client's OAQDQ call, it dequeues a message
+type AQDequeue struct {
+	Sequence    uint8
+	Queue       string
+	Consumer    string // Consumer of multi-consumer queues
+	Mode        uint32 // Browse, locked, remove or remove no data
+	Navigation  uint32
+	Visibility  uint32
+	Wait        int32  // Seconds to wait for a message, -1 for ever
+	MessageId   []byte // Id of the message to dequeue, nil for the next one
+	Correlation string // Correlation of the message to dequeue
+	Condition   string // Condition on the message properties or payload
+	PayloadType []byte // TOID of the payload type
+}
+
+func (m AQDequeue) Code() TTCCode { return TTIFUN }
+
+// Call gives the dequeue function call, without its body
+func (m AQDequeue) Call() FunctionCall {
+	return FunctionCall{Function: OAQDQ, Sequence: m.Sequence}
+}
+
+// readAQDequeue decodes the dequeue call, its layout depends on the TTC version
+func (c TTCContext) readAQDequeue(buff *bytes.Buffer, call FunctionCall) (AQDequeue, error) {
+	var err error
+	m := AQDequeue{Sequence: call.Sequence}
+
+	var queueLen, consumerLen, idLen, correlationLen, toidLen, conditionLen uint32
+	queueLen, err = readPointerLength(buff)
+	if err != nil {
+		return m, err
+	}
+	err = SkipUInts(buff, 1, 1, 1, 1) // Message properties and recipients pointers
+	if err != nil {
+		return m, err
+	}
+	consumerLen, err = readPointerLength(buff)
+	if err != nil {
+		return m, err
+	}
+	m.Mode, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Navigation, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Visibility, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Wait, err = GetInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	idLen, err = readPointerLength(buff)
+	if err != nil {
+		return m, err
+	}
+	correlationLen, err = readPointerLength(buff)
+	if err != nil {
+		return m, err
+	}
+	toidLen, err = readPointerLength(buff)
+	if err != nil {
+		return m, err
+	}
+	// Payload version, payload pointer, returned message id and flags
+	err = SkipUInts(buff, 2, 1, 1, 4, 4)
+	if err != nil {
+		return m, err
+	}
+	conditionLen, err = readPointerLength(buff)
+	if err != nil {
+		return m, err
+	}
+	err = SkipUInts(buff, 1, 4) // Extensions
+	if err != nil {
+		return m, err
+	}
+	if c.TTCVersion >= 16 {
+		err = SkipUInts(buff, 1, 4) // JSON payload pointer and shard id
+		if err != nil {
+			return m, err
+		}
+	}
+
+	m.Queue, err = readAQString(buff, queueLen)
+	if err != nil {
+		return m, err
+	}
+	m.Consumer, err = readAQString(buff, consumerLen)
+	if err != nil {
+		return m, err
+	}
+	m.MessageId, err = readLocator(buff, int(idLen))
+	if err != nil {
+		return m, err
+	}
+	m.Correlation, err = readAQString(buff, correlationLen)
+	if err != nil {
+		return m, err
+	}
+	m.PayloadType, err = readLocator(buff, int(toidLen))
+	if err != nil {
+		return m, err
+	}
+	m.Condition, err = readAQString(buff, conditionLen)
+	return m, err
+}
+
+// readPointerLength reads the pointer byte in front of a length, then the length
+func readPointerLength(buff *bytes.Buffer) (uint32, error) {
+	err := SkipUInts(buff, 1)
+	if err != nil {
+		return 0, err
+	}
+	return GetUInt(buff, 4, true, true)
+}
+
+// readAQString reads a string whose length is given before, an empty string when the length is 0
+func readAQString(buff *bytes.Buffer, l uint32) (string, error) {
+	if l == 0 {
+		return "", nil
+	}
+	b, err := ReadBytes(buff)
+	return string(b), err
+}
+
+// readAQBytes reads bytes preceded by their length
+func readAQBytes(buff *bytes.Buffer) ([]byte, error) {
+	l, err := GetUInt(buff, 4, true, true)
+	if err != nil || l == 0 {
+		return nil, err
+	}
+	return ReadBytes(buff)
+}
+
+// readAQMessageProperties reads the message properties, the same layout is used in both directions
+func (c TTCContext) readAQMessageProperties(buff *bytes.Buffer) (AQMessageProperties, error) {
+	var err error
+	var b []byte
+	m := AQMessageProperties{}
+
+	m.Priority, err = GetInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Delay, err = GetInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Expiration, err = GetInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	b, err = readAQBytes(buff)
+	if err != nil {
+		return m, err
+	}
+	m.Correlation = string(b)
+	m.Attempts, err = GetInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	b, err = readAQBytes(buff)
+	if err != nil {
+		return m, err
+	}
+	m.ExceptionQueue = string(b)
+	m.State, err = GetInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	_, err = readAQBytes(buff) // Enqueue time
+	if err != nil {
+		return m, err
+	}
+	_, err = readAQBytes(buff) // Enqueue transaction id
+	if err != nil {
+		return m, err
+	}
+
+	// Extensions: agent name, address and protocol, original message id
+	var n uint32
+	n, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	if n > 0 {
+		err = SkipUInts(buff, 1)
+		if err != nil {
+			return m, err
+		}
+	}
+	for i := 0; i < int(n); i++ {
+		_, err = readAQBytes(buff) // Text value
+		if err != nil {
+			return m, err
+		}
+		_, err = readAQBytes(buff) // Binary value
+		if err != nil {
+			return m, err
+		}
+		err = SkipUInts(buff, 2) // Keyword
+		if err != nil {
+			return m, err
+		}
+	}
+
+	// User properties, commit SCNs and flags
+	err = SkipUInts(buff, 4, 4, 4, 4)
+	if err != nil {
+		return m, err
+	}
+	if c.TTCVersion >= 16 {
+		err = SkipUInts(buff, 4) // Shard id
+	}
+	return m, err
+}
+
+// AQEnqueueParameters are returned by the server for an OAQEQ call
+type AQEnqueueParameters struct {
+	MessageId []byte // Id given to the message
+}
+
+func (m AQEnqueueParameters) Code() TTCCode { return TTIRPA }
+
+func readAQEnqueueParameters(buff *bytes.Buffer) (AQEnqueueParameters, error) {
+	m := AQEnqueueParameters{}
+	if buff.Len() < AQMessageIdLength {
+		return m, io.EOF
+	}
+	m.MessageId = buff.Next(AQMessageIdLength)
+	return m, nil
+}
+
+// AQDequeueParameters are returned by the server for an OAQDQ call
+type AQDequeueParameters struct {
+	Found      bool // A message is dequeued
+	Properties AQMessageProperties
+	Remaining  []byte // Payload, message id and the end of the response, not decoded
+}
+
+func (m AQDequeueParameters) Code() TTCCode { return TTIRPA }
+
+func (c TTCContext) readAQDequeueParameters(buff *bytes.Buffer) (AQDequeueParameters, error) {
+	var err error
+	m := AQDequeueParameters{}
+
+	var n uint32
+	n, err = GetUInt(buff, 4, true, true) // Message properties length
+	if err != nil || n == 0 {
+		return m, err
+	}
+	m.Found = true
+	m.Properties, err = c.readAQMessageProperties(buff)
+	if err != nil {
+		return m, err
+	}
+	m.Remaining = buff.Next(buff.Len())
+	return m, nil
+}
@@ -0,0 +1,121 @@
+package packet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadAQMessages(t *testing.T) {
+	props := AQMessageProperties{Priority: 2, Expiration: -1, Correlation: "ORD-42"}
+	toid := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x17}
+	tests := []struct {
+		name string
+		ctx  TTCContext
+		b    []byte
+		want []TTCMessage
+	}{
+		{
+			name: "enqueue call",
+			ctx:  TTCContext{TTCVersion: 11},
+			b: []byte{
+				0x00, 0x81, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x79, 0x05, 0x01, 0x01, 0x0c,
+				0x01, 0x02, 0x00, 0x81, 0x01, 0x01, 0x06, 0x06, 0x4f, 0x52, 0x44, 0x2d, 0x34, 0x32, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x01, 0x04, 0x0e, 0x00, 0x00, 0x01, 0x40, 0x00, 0x00, 0x01, 0x41, 0x00, 0x01,
+				0x01, 0x01, 0x00, 0x01, 0x42, 0x00, 0x00, 0x01, 0x45, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+				0x02, 0x00, 0x00, 0x00, 0x01, 0x01, 0x10, 0x00, 0x01, 0x01, 0x01, 0x10, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0c, 0x41,
+				0x50, 0x50, 0x2e, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x53, 0x5f, 0x51, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x17, 0x05, 0x68, 0x65, 0x6c, 0x6c,
+				0x6f,
+			},
+			want: []TTCMessage{
+				AQEnqueue{
+					Sequence:    5,
+					Queue:       "APP.ORDERS_Q",
+					Properties:  props,
+					Visibility:  2,
+					PayloadType: toid,
+					Payload:     []byte{0x05, 0x68, 0x65, 0x6c, 0x6c, 0x6f},
+				},
+			},
+		},
+		{
+			name: "enqueue response",
+			ctx:  TTCContext{FromServer: true, Function: OAQEQ, TTCVersion: 11},
+			b: []byte{
+				0x00, 0x3a, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x10, 0x11, 0x12, 0x13, 0x14,
+				0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f, 0x04, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09, 0x00, 0x01, 0x05,
+			},
+			want: []TTCMessage{
+				AQEnqueueParameters{MessageId: []byte{0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f}},
+				Summary{},
+				Status{Sequence: 5},
+			},
+		},
+		{
+			name: "dequeue call",
+			ctx:  TTCContext{TTCVersion: 11},
+			b: []byte{
+				0x00, 0x55, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x7a, 0x06, 0x01, 0x01, 0x0c,
+				0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x07, 0x01, 0x03, 0x01, 0x03, 0x01, 0x02, 0x01, 0x0a, 0x00,
+				0x00, 0x00, 0x00, 0x01, 0x01, 0x10, 0x00, 0x01, 0x01, 0x01, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x0c, 0x41, 0x50, 0x50, 0x2e, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x53, 0x5f, 0x51, 0x07, 0x42, 0x49,
+				0x4c, 0x4c, 0x49, 0x4e, 0x47, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x17,
+			},
+			want: []TTCMessage{
+				AQDequeue{
+					Sequence:    6,
+					Queue:       "APP.ORDERS_Q",
+					Consumer:    "BILLING",
+					Mode:        AQRemove,
+					Navigation:  3,
+					Visibility:  2,
+					Wait:        10,
+					PayloadType: toid,
+				},
+			},
+		},
+		{
+			name: "dequeue response",
+			ctx:  TTCContext{FromServer: true, Function: OAQDQ, TTCVersion: 11},
+			b: []byte{
+				0x00, 0x3c, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x01, 0x30, 0x01, 0x02, 0x00,
+				0x81, 0x01, 0x01, 0x06, 0x06, 0x4f, 0x52, 0x44, 0x2d, 0x34, 0x32, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x01, 0x04, 0x0e, 0x00, 0x00, 0x01, 0x40, 0x00, 0x00, 0x01, 0x41, 0x00, 0x01, 0x01, 0x01, 0x00,
+				0x01, 0x42, 0x00, 0x00, 0x01, 0x45, 0x00, 0x00, 0x00, 0x00, 0xaa, 0xbb,
+			},
+			want: []TTCMessage{
+				AQDequeueParameters{Found: true, Properties: props, Remaining: []byte{0xaa, 0xbb}},
+			},
+		},
+		{
+			name: "dequeue response without message",
+			ctx:  TTCContext{FromServer: true, Function: OAQDQ, TTCVersion: 11},
+			b: []byte{
+				0x00, 0x2b, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x04, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09, 0x00, 0x01, 0x05,
+			},
+			want: []TTCMessage{
+				AQDequeueParameters{},
+				Summary{},
+				Status{Sequence: 5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ctx.ReadMessages(tt.b)
+			if err != nil {
+				t.Errorf("ReadMessages() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadMessages() = \n%#v,\n want \n%#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package packet
+
+import (
+	"bytes"
+	"strings"
+)
+
+// AuthCall is the client's OSESSKEY or OAUTH call of the authentication phase.
+// Password verifiers, session keys and alike are never kept.
+type AuthCall struct {
+	Function  FunctionCode
+	Sequence  uint8
+	User      string     // Database user name
+	Mode      uint32     // Logon mode
+	KeyValues []KeyValue // Key / values sent by the client, secrets excluded
+}
+
+func (m AuthCall) Code() TTCCode { return TTIFUN }
+
+// Call gives the function call of the authentication, without its body
+func (m AuthCall) Call() FunctionCall {
+	return FunctionCall{Function: m.Function, Sequence: m.Sequence}
+}
+
+func readAuthCall(buff *bytes.Buffer, call FunctionCall) (AuthCall, error) {
+	var err error
+	m := AuthCall{Function: call.Function, Sequence: call.Sequence}
+
+	err = SkipUInts(buff, 1) // User pointer
+	if err != nil {
+		return m, err
+	}
+	var l, n uint32
+	l, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Mode, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	err = SkipUInts(buff, 1) // Key / values pointer
+	if err != nil {
+		return m, err
+	}
+	n, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	err = SkipUInts(buff, 1, 1) // Output key / values count and array pointers
+	if err != nil {
+		return m, err
+	}
+
+	if l > 0 {
+		err = checkLength(buff, int(l))
+		if err != nil {
+			return m, err
+		}
+		// Some clients send the user name with its length, other don't
+		b := buff.Bytes()
+		if len(b) > int(l) && b[0] == byte(l) {
+			buff.Next(1)
+		}
+		m.User = string(buff.Next(int(l)))
+	}
+
+	m.KeyValues, err = readAuthKeyValues(buff, n)
+	return m, err
+}
+
+// AuthParameters is the server's answer to the OSESSKEY or OAUTH call.
+// Password verifiers, session keys and alike are never kept.
+type AuthParameters struct {
+	KeyValues []KeyValue
+}
+
+func (m AuthParameters) Code() TTCCode { return TTIRPA }
+
+func readAuthParameters(buff *bytes.Buffer) (AuthParameters, error) {
+	m := AuthParameters{}
+	n, err := GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.KeyValues, err = readAuthKeyValues(buff, n)
+	return m, err
+}
+
+// readAuthKeyValues reads n key / values and drops the secret ones
+func readAuthKeyValues(buff *bytes.Buffer, n uint32) ([]KeyValue, error) {
+	kvs := []KeyValue{}
+	for i := 0; i < int(n); i++ {
+		kv := KeyValue{}
+		var err error
+		kv.Key, kv.Value, kv.Flag, err = readKeyVal(buff)
+		if err != nil {
+			return kvs, err
+		}
+		if isSecret(string(kv.Key)) {
+			continue
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs, nil
+}
+
+// secrets are parts of key names of the authentication that must not be disclosed
+var secrets = []string{"SESSKEY", "PASSWORD", "VFR", "PBKDF2", "SPEEDY", "TOKEN", "SALT", "VERIFIER"}
+
+func isSecret(key string) bool {
+	key = strings.ToUpper(key)
+	for _, s := range secrets {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Login gathers the information exchanged during the authentication
+type Login struct {
+	User      string // Database user
+	OSUser    string // Client's OS user
+	Terminal  string // Client's terminal
+	Program   string // Client's program name
+	Machine   string // Client's machine
+	ClientPid string // Client's process and thread id
+	SessionId string // Server's session id (SID)
+	SerialNum string // Server's session serial number
+	Database  string // Database name
+	Instance  string // Instance name
+	Service   string // Service name
+}
+
+// Add takes the information of an authentication message, other messages are ignored
+func (l *Login) Add(m TTCMessage) {
+	var kvs []KeyValue
+	switch m := m.(type) {
+	case AuthCall:
+		if m.User != "" {
+			l.User = m.User
+		}
+		kvs = m.KeyValues
+	case AuthParameters:
+		kvs = m.KeyValues
+	default:
+		return
+	}
+	for _, kv := range kvs {
+		var f *string
+		switch string(kv.Key) {
+		case "AUTH_SID":
+			f = &l.OSUser
+		case "AUTH_TERMINAL":
+			f = &l.Terminal
+		case "AUTH_PROGRAM_NM":
+			f = &l.Program
+		case "AUTH_MACHINE":
+			f = &l.Machine
+		case "AUTH_PID":
+			f = &l.ClientPid
+		case "AUTH_SESSION_ID":
+			f = &l.SessionId
+		case "AUTH_SERIAL_NUM":
+			f = &l.SerialNum
+		case "AUTH_DBNAME":
+			f = &l.Database
+		case "AUTH_INSTANCENAME":
+			f = &l.Instance
+		case "AUTH_SC_SERVICE_NAME":
+			f = &l.Service
+		default:
+			continue
+		}
+		if len(kv.Value) > 0 {
+			*f = string(kv.Value)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package packet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadAuth(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  TTCContext
+		b    []byte
+		want []TTCMessage
+	}{
+		{
+			name: "session key, user with its length",
+			b: []byte{
+				0x00, 0xa4, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x76, 0x02, 0x01, 0x01, 0x05,
+				0x01, 0x01, 0x01, 0x01, 0x05, 0x01, 0x01, 0x05, 0x53, 0x43, 0x4f, 0x54, 0x54, 0x01, 0x0d, 0x0d,
+				0x41, 0x55, 0x54, 0x48, 0x5f, 0x54, 0x45, 0x52, 0x4d, 0x49, 0x4e, 0x41, 0x4c, 0x01, 0x05, 0x05,
+				0x50, 0x43, 0x2d, 0x34, 0x32, 0x00, 0x01, 0x0f, 0x0f, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x50, 0x52,
+				0x4f, 0x47, 0x52, 0x41, 0x4d, 0x5f, 0x4e, 0x4d, 0x01, 0x0b, 0x0b, 0x73, 0x71, 0x6c, 0x70, 0x6c,
+				0x75, 0x73, 0x2e, 0x65, 0x78, 0x65, 0x00, 0x01, 0x0c, 0x0c, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x4d,
+				0x41, 0x43, 0x48, 0x49, 0x4e, 0x45, 0x01, 0x0f, 0x0f, 0x57, 0x4f, 0x52, 0x4b, 0x47, 0x52, 0x4f,
+				0x55, 0x50, 0x5c, 0x50, 0x43, 0x2d, 0x34, 0x32, 0x00, 0x01, 0x08, 0x08, 0x41, 0x55, 0x54, 0x48,
+				0x5f, 0x50, 0x49, 0x44, 0x01, 0x09, 0x09, 0x35, 0x32, 0x33, 0x36, 0x3a, 0x35, 0x32, 0x34, 0x30,
+				0x00, 0x01, 0x08, 0x08, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x53, 0x49, 0x44, 0x01, 0x04, 0x04, 0x6a,
+				0x64, 0x6f, 0x65, 0x00,
+			},
+			want: []TTCMessage{
+				AuthCall{
+					Function: OSESSKEY,
+					Sequence: 2,
+					User:     "SCOTT",
+					Mode:     1,
+					KeyValues: []KeyValue{
+						{Key: []byte("AUTH_TERMINAL"), Value: []byte("PC-42")},
+						{Key: []byte("AUTH_PROGRAM_NM"), Value: []byte("sqlplus.exe")},
+						{Key: []byte("AUTH_MACHINE"), Value: []byte("WORKGROUP\\PC-42")},
+						{Key: []byte("AUTH_PID"), Value: []byte("5236:5240")},
+						{Key: []byte("AUTH_SID"), Value: []byte("jdoe")},
+					},
+				},
+			},
+		},
+		{
+			name: "session key answer, secrets dropped",
+			ctx:  TTCContext{FromServer: true, Function: OSESSKEY},
+			b: []byte{
+				0x00, 0x4f, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x01, 0x02, 0x01, 0x0c, 0x0c,
+				0x41, 0x55, 0x54, 0x48, 0x5f, 0x53, 0x45, 0x53, 0x53, 0x4b, 0x45, 0x59, 0x01, 0x0c, 0x0c, 0x30,
+				0x41, 0x31, 0x42, 0x32, 0x43, 0x33, 0x44, 0x34, 0x45, 0x35, 0x46, 0x01, 0x01, 0x01, 0x0d, 0x0d,
+				0x41, 0x55, 0x54, 0x48, 0x5f, 0x56, 0x46, 0x52, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x01, 0x08, 0x08,
+				0x46, 0x30, 0x30, 0x44, 0x46, 0x30, 0x30, 0x44, 0x02, 0x1b, 0x25, 0x09, 0x00, 0x01, 0x03,
+			},
+			want: []TTCMessage{
+				AuthParameters{KeyValues: []KeyValue{}},
+				Status{Sequence: 3},
+			},
+		},
+		{
+			name: "authentication, secrets dropped",
+			b: []byte{
+				0x00, 0xdc, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x73, 0x03, 0x01, 0x01, 0x05,
+				0x02, 0x01, 0x01, 0x01, 0x01, 0x07, 0x01, 0x01, 0x53, 0x43, 0x4f, 0x54, 0x54, 0x01, 0x0d, 0x0d,
+				0x41, 0x55, 0x54, 0x48, 0x5f, 0x50, 0x41, 0x53, 0x53, 0x57, 0x4f, 0x52, 0x44, 0x01, 0x08, 0x08,
+				0x44, 0x45, 0x41, 0x44, 0x42, 0x45, 0x45, 0x46, 0x00, 0x01, 0x0c, 0x0c, 0x41, 0x55, 0x54, 0x48,
+				0x5f, 0x53, 0x45, 0x53, 0x53, 0x4b, 0x45, 0x59, 0x01, 0x08, 0x08, 0x43, 0x41, 0x46, 0x45, 0x42,
+				0x41, 0x42, 0x45, 0x01, 0x01, 0x01, 0x0d, 0x0d, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x54, 0x45, 0x52,
+				0x4d, 0x49, 0x4e, 0x41, 0x4c, 0x01, 0x05, 0x05, 0x50, 0x43, 0x2d, 0x34, 0x32, 0x00, 0x01, 0x0f,
+				0x0f, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x50, 0x52, 0x4f, 0x47, 0x52, 0x41, 0x4d, 0x5f, 0x4e, 0x4d,
+				0x01, 0x0b, 0x0b, 0x73, 0x71, 0x6c, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x65, 0x78, 0x65, 0x00, 0x01,
+				0x0c, 0x0c, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x4d, 0x41, 0x43, 0x48, 0x49, 0x4e, 0x45, 0x01, 0x0f,
+				0x0f, 0x57, 0x4f, 0x52, 0x4b, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5c, 0x50, 0x43, 0x2d, 0x34, 0x32,
+				0x00, 0x01, 0x08, 0x08, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x50, 0x49, 0x44, 0x01, 0x09, 0x09, 0x35,
+				0x32, 0x33, 0x36, 0x3a, 0x35, 0x32, 0x34, 0x30, 0x00, 0x01, 0x08, 0x08, 0x41, 0x55, 0x54, 0x48,
+				0x5f, 0x53, 0x49, 0x44, 0x01, 0x04, 0x04, 0x6a, 0x64, 0x6f, 0x65, 0x00,
+			},
+			want: []TTCMessage{
+				AuthCall{
+					Function: OAUTH,
+					Sequence: 3,
+					User:     "SCOTT",
+					Mode:     0x101,
+					KeyValues: []KeyValue{
+						{Key: []byte("AUTH_TERMINAL"), Value: []byte("PC-42")},
+						{Key: []byte("AUTH_PROGRAM_NM"), Value: []byte("sqlplus.exe")},
+						{Key: []byte("AUTH_MACHINE"), Value: []byte("WORKGROUP\\PC-42")},
+						{Key: []byte("AUTH_PID"), Value: []byte("5236:5240")},
+						{Key: []byte("AUTH_SID"), Value: []byte("jdoe")},
+					},
+				},
+			},
+		},
+		{
+			name: "authentication answer",
+			ctx:  TTCContext{FromServer: true, Function: OAUTH},
+			b: []byte{
+				0x00, 0x86, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x01, 0x04, 0x01, 0x13, 0x13,
+				0x41, 0x55, 0x54, 0x48, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x52,
+				0x49, 0x4e, 0x47, 0x01, 0x0c, 0x0c, 0x2d, 0x20, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x69,
+				0x6f, 0x6e, 0x00, 0x01, 0x0f, 0x0f, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x53, 0x45, 0x53, 0x53, 0x49,
+				0x4f, 0x4e, 0x5f, 0x49, 0x44, 0x01, 0x03, 0x03, 0x31, 0x33, 0x36, 0x00, 0x01, 0x0f, 0x0f, 0x41,
+				0x55, 0x54, 0x48, 0x5f, 0x53, 0x45, 0x52, 0x49, 0x41, 0x4c, 0x5f, 0x4e, 0x55, 0x4d, 0x01, 0x04,
+				0x04, 0x32, 0x38, 0x31, 0x37, 0x00, 0x01, 0x11, 0x11, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x49, 0x4e,
+				0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x4e, 0x41, 0x4d, 0x45, 0x01, 0x04, 0x04, 0x6f, 0x72, 0x63,
+				0x6c, 0x00, 0x09, 0x00, 0x01, 0x04,
+			},
+			want: []TTCMessage{
+				AuthParameters{KeyValues: []KeyValue{
+					{Key: []byte("AUTH_VERSION_STRING"), Value: []byte("- Production")},
+					{Key: []byte("AUTH_SESSION_ID"), Value: []byte("136")},
+					{Key: []byte("AUTH_SERIAL_NUM"), Value: []byte("2817")},
+					{Key: []byte("AUTH_INSTANCENAME"), Value: []byte("orcl")},
+				}},
+				Status{Sequence: 4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ctx.ReadMessages(tt.b)
+			if err != nil {
+				t.Errorf("ReadMessages() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadMessages() = \n%#v,\n want \n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogin_Add(t *testing.T) {
+	l := Login{}
+	l.Add(AuthCall{User: "SCOTT", KeyValues: []KeyValue{{Key: []byte("AUTH_PROGRAM_NM"), Value: []byte("sqlplus.exe")}, {Key: []byte("AUTH_SID"), Value: []byte("jdoe")}}})
+	l.Add(AuthParameters{KeyValues: []KeyValue{{Key: []byte("AUTH_SESSION_ID"), Value: []byte("136")}, {Key: []byte("AUTH_SERIAL_NUM"), Value: []byte("2817")}}})
+	want := Login{User: "SCOTT", Program: "sqlplus.exe", OSUser: "jdoe", SessionId: "136", SerialNum: "2817"}
+	if l != want {
+		t.Errorf("Login = %+v, want %+v", l, want)
+	}
+}
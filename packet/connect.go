@@ -0,0 +1,179 @@
+package packet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// ConnectPacket is sent by the client to open a connection
+type ConnectPacket struct {
+	TNSHeader                        // ofs: 0 Len 8
+	Version                   uint16 // ofs: 8
+	CompatibleVersion         uint16 // ofs: 10
+	ServiceOptions            uint16 // ofs: 12
+	SDU                       uint32 // ofs: 14, ofs: 58 on 4 bytes from version 315
+	TDU                       uint32 // ofs: 16, ofs: 62 on 4 bytes from version 315
+	NTProtocolCharacteristics uint16 // ofs: 18
+	LineTurnaround            uint16 // ofs: 20
+	ValueOfOne                uint16 // ofs: 22
+	ConnectDataLength         uint16 // ofs: 24
+	ConnectDataOffset         uint16 // ofs: 26
+	MaxReceivableData         uint32 // ofs: 28
+	ConnectFlags0             uint8  // ofs: 32
+	ConnectFlags1             uint8  // ofs: 33
+	ConnectData               string // Connect descriptor at ConnectDataOffset
+}
+
+func ReadConnectPacket(b []byte) ConnectPacket {
+	cp := ConnectPacket{
+		TNSHeader:                 ReadTNSHeader(b),
+		Version:                   getUint16(b, 8),
+		CompatibleVersion:         getUint16(b, 10),
+		ServiceOptions:            getUint16(b, 12),
+		SDU:                       uint32(getUint16(b, 14)),
+		TDU:                       uint32(getUint16(b, 16)),
+		NTProtocolCharacteristics: getUint16(b, 18),
+		LineTurnaround:            getUint16(b, 20),
+		ValueOfOne:                getUint16(b, 22),
+		ConnectDataLength:         getUint16(b, 24),
+		ConnectDataOffset:         getUint16(b, 26),
+		MaxReceivableData:         getUint32(b, 28),
+		ConnectFlags0:             getUint8(b, 32),
+		ConnectFlags1:             getUint8(b, 33),
+	}
+	if cp.Version >= 315 && cp.ConnectDataOffset >= 66 {
+		cp.SDU = getUint32(b, 58)
+		cp.TDU = getUint32(b, 62)
+	}
+	cp.ConnectData = getString(b, int(cp.ConnectDataOffset), int(cp.ConnectDataLength))
+	return cp
+}
+
+func (cp ConnectPacket) String() string {
+	sb := strings.Builder{}
+	cp.StringBuilder(&sb)
+	return sb.String()
+}
+
+func (cp ConnectPacket) StringBuilder(sb *strings.Builder) {
+	cp.TNSHeader.writeFields(sb)
+	writeEol(sb)
+	cp.writeFields(sb)
+	writeEol(sb)
+	writePayload(sb, cp.TNSHeader.Buffer)
+}
+
+func (cp ConnectPacket) writeFields(sb *strings.Builder) {
+	sb.WriteString("Connect packet fields: ")
+	sb.WriteString(fmt.Sprintf("Version(%d),", cp.Version))
+	sb.WriteString(fmt.Sprintf("CompatibleVersion(%d),", cp.CompatibleVersion))
+	sb.WriteString(fmt.Sprintf("ServiceOptions(%04x),", cp.ServiceOptions))
+	sb.WriteString(fmt.Sprintf("SDU(%d),", cp.SDU))
+	sb.WriteString(fmt.Sprintf("TDU(%d),", cp.TDU))
+	sb.WriteString(fmt.Sprintf("NTProtocolCharacteristics(%04x),", cp.NTProtocolCharacteristics))
+	sb.WriteString(fmt.Sprintf("MaxReceivableData(%d),", cp.MaxReceivableData))
+	sb.WriteString(fmt.Sprintf("ConnectFlags(%02x,%02x)", cp.ConnectFlags0, cp.ConnectFlags1))
+	writeEol(sb)
+	sb.WriteString("Connect data: ")
+	sb.WriteString(cp.ConnectData)
+}
+
+// AcceptPacket is the server's answer to the connect packet
+type AcceptPacket struct {
+	TNSHeader               // ofs: 0 Len 8
+	Version          uint16 // ofs: 8
+	ServiceOptions   uint16 // ofs: 10
+	SDU              uint32 // ofs: 12, ofs: 32 on 4 bytes from version 315
+	TDU              uint32 // ofs: 14, ofs: 36 on 4 bytes from version 315
+	ValueOfOne       uint16 // ofs: 16
+	AcceptDataLength uint16 // ofs: 18
+	AcceptDataOffset uint16 // ofs: 20
+	ConnectFlags0    uint8  // ofs: 22
+	ConnectFlags1    uint8  // ofs: 23
+	AcceptData       string // Accept data at AcceptDataOffset
+}
+
+func ReadAcceptPacket(b []byte) AcceptPacket {
+	ap := AcceptPacket{
+		TNSHeader:        ReadTNSHeader(b),
+		Version:          getUint16(b, 8),
+		ServiceOptions:   getUint16(b, 10),
+		SDU:              uint32(getUint16(b, 12)),
+		TDU:              uint32(getUint16(b, 14)),
+		ValueOfOne:       getUint16(b, 16),
+		AcceptDataLength: getUint16(b, 18),
+		AcceptDataOffset: getUint16(b, 20),
+		ConnectFlags0:    getUint8(b, 22),
+		ConnectFlags1:    getUint8(b, 23),
+	}
+	if ap.Version >= 315 {
+		ap.SDU = getUint32(b, 32)
+		ap.TDU = getUint32(b, 36)
+	}
+	ap.AcceptData = getString(b, int(ap.AcceptDataOffset), int(ap.AcceptDataLength))
+	return ap
+}
+
+func (ap AcceptPacket) String() string {
+	sb := strings.Builder{}
+	ap.StringBuilder(&sb)
+	return sb.String()
+}
+
+func (ap AcceptPacket) StringBuilder(sb *strings.Builder) {
+	ap.TNSHeader.writeFields(sb)
+	writeEol(sb)
+	ap.writeFields(sb)
+	writeEol(sb)
+	writePayload(sb, ap.TNSHeader.Buffer)
+}
+
+func (ap AcceptPacket) writeFields(sb *strings.Builder) {
+	sb.WriteString("Accept packet fields: ")
+	sb.WriteString(fmt.Sprintf("Version(%d),", ap.Version))
+	sb.WriteString(fmt.Sprintf("ServiceOptions(%04x),", ap.ServiceOptions))
+	sb.WriteString(fmt.Sprintf("SDU(%d),", ap.SDU))
+	sb.WriteString(fmt.Sprintf("TDU(%d),", ap.TDU))
+	sb.WriteString(fmt.Sprintf("ConnectFlags(%02x,%02x)", ap.ConnectFlags0, ap.ConnectFlags1))
+	if ap.AcceptData != "" {
+		writeEol(sb)
+		sb.WriteString("Accept data: ")
+		sb.WriteString(ap.AcceptData)
+	}
+}
+
+// getUint8 returns the byte at ofs, or 0 when the packet is too short
+func getUint8(b []byte, ofs int) uint8 {
+	if ofs+1 > len(b) {
+		return 0
+	}
+	return b[ofs]
+}
+
+// getUint16 returns the big endian uint16 at ofs, or 0 when the packet is too short
+func getUint16(b []byte, ofs int) uint16 {
+	if ofs+2 > len(b) {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b[ofs:])
+}
+
+// getUint32 returns the big endian uint32 at ofs, or 0 when the packet is too short
+func getUint32(b []byte, ofs int) uint32 {
+	if ofs+4 > len(b) {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b[ofs:])
+}
+
+// getString returns the string at ofs, truncated to the packet's end
+func getString(b []byte, ofs int, l int) string {
+	if l == 0 || ofs >= len(b) {
+		return ""
+	}
+	if ofs+l > len(b) {
+		l = len(b) - ofs
+	}
+	return string(b[ofs : ofs+l])
+}
@@ -0,0 +1,142 @@
+package packet
+
+import (
+	"testing"
+)
+
+func TestReadConnectPacket(t *testing.T) {
+	connectData := "(DESCRIPTION=(CONNECT_DATA=(SERVICE_NAME=ORCL)(CID=(PROGRAM=sqlplus)(HOST=PC1)(USER=scott)))(ADDRESS=(PROTOCOL=TCP)(HOST=db1)(PORT=1521)))"
+	tests := []struct {
+		name string
+		b    []byte
+		want ConnectPacket
+	}{
+		{
+			name: "version 314",
+			b: []byte{
+				0x00, 0xc4, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x3a, 0x01, 0x2c, 0x0c, 0x41, 0x20, 0x00,
+				0xff, 0xff, 0x7f, 0x08, 0x00, 0x00, 0x00, 0x01, 0x00, 0x8a, 0x00, 0x3a, 0x00, 0x00, 0x00, 0x00,
+				0x41, 0x41, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x28, 0x44, 0x45, 0x53, 0x43, 0x52,
+				0x49, 0x50, 0x54, 0x49, 0x4f, 0x4e, 0x3d, 0x28, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x5f,
+				0x44, 0x41, 0x54, 0x41, 0x3d, 0x28, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x4e, 0x41,
+				0x4d, 0x45, 0x3d, 0x4f, 0x52, 0x43, 0x4c, 0x29, 0x28, 0x43, 0x49, 0x44, 0x3d, 0x28, 0x50, 0x52,
+				0x4f, 0x47, 0x52, 0x41, 0x4d, 0x3d, 0x73, 0x71, 0x6c, 0x70, 0x6c, 0x75, 0x73, 0x29, 0x28, 0x48,
+				0x4f, 0x53, 0x54, 0x3d, 0x50, 0x43, 0x31, 0x29, 0x28, 0x55, 0x53, 0x45, 0x52, 0x3d, 0x73, 0x63,
+				0x6f, 0x74, 0x74, 0x29, 0x29, 0x29, 0x28, 0x41, 0x44, 0x44, 0x52, 0x45, 0x53, 0x53, 0x3d, 0x28,
+				0x50, 0x52, 0x4f, 0x54, 0x4f, 0x43, 0x4f, 0x4c, 0x3d, 0x54, 0x43, 0x50, 0x29, 0x28, 0x48, 0x4f,
+				0x53, 0x54, 0x3d, 0x64, 0x62, 0x31, 0x29, 0x28, 0x50, 0x4f, 0x52, 0x54, 0x3d, 0x31, 0x35, 0x32,
+				0x31, 0x29, 0x29, 0x29,
+			},
+			want: ConnectPacket{
+				Version:                   314,
+				CompatibleVersion:         300,
+				ServiceOptions:            0x0c41,
+				SDU:                       8192,
+				TDU:                       65535,
+				NTProtocolCharacteristics: 0x7f08,
+				ConnectFlags0:             0x41,
+				ConnectFlags1:             0x41,
+				ConnectData:               connectData,
+			},
+		},
+		{
+			name: "version 318 with large SDU",
+			b: []byte{
+				0x00, 0xd4, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x3e, 0x01, 0x2c, 0x0c, 0x41, 0xff, 0xff,
+				0xff, 0xff, 0x7f, 0x08, 0x00, 0x00, 0x00, 0x01, 0x00, 0x8a, 0x00, 0x4a, 0x00, 0x00, 0x00, 0x00,
+				0x41, 0x41, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x20, 0x00, 0x00, 0x00, 0x20,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x28, 0x44, 0x45, 0x53, 0x43, 0x52,
+				0x49, 0x50, 0x54, 0x49, 0x4f, 0x4e, 0x3d, 0x28, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x5f,
+				0x44, 0x41, 0x54, 0x41, 0x3d, 0x28, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x4e, 0x41,
+				0x4d, 0x45, 0x3d, 0x4f, 0x52, 0x43, 0x4c, 0x29, 0x28, 0x43, 0x49, 0x44, 0x3d, 0x28, 0x50, 0x52,
+				0x4f, 0x47, 0x52, 0x41, 0x4d, 0x3d, 0x73, 0x71, 0x6c, 0x70, 0x6c, 0x75, 0x73, 0x29, 0x28, 0x48,
+				0x4f, 0x53, 0x54, 0x3d, 0x50, 0x43, 0x31, 0x29, 0x28, 0x55, 0x53, 0x45, 0x52, 0x3d, 0x73, 0x63,
+				0x6f, 0x74, 0x74, 0x29, 0x29, 0x29, 0x28, 0x41, 0x44, 0x44, 0x52, 0x45, 0x53, 0x53, 0x3d, 0x28,
+				0x50, 0x52, 0x4f, 0x54, 0x4f, 0x43, 0x4f, 0x4c, 0x3d, 0x54, 0x43, 0x50, 0x29, 0x28, 0x48, 0x4f,
+				0x53, 0x54, 0x3d, 0x64, 0x62, 0x31, 0x29, 0x28, 0x50, 0x4f, 0x52, 0x54, 0x3d, 0x31, 0x35, 0x32,
+				0x31, 0x29, 0x29, 0x29,
+			},
+			want: ConnectPacket{
+				Version:                   318,
+				CompatibleVersion:         300,
+				ServiceOptions:            0x0c41,
+				SDU:                       2097152,
+				TDU:                       2097152,
+				NTProtocolCharacteristics: 0x7f08,
+				ConnectFlags0:             0x41,
+				ConnectFlags1:             0x41,
+				ConnectData:               connectData,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReadConnectPacket(tt.b)
+			if got.PkType != Connect {
+				t.Errorf("PkType = %d, want %d", got.PkType, Connect)
+			}
+			if got.Version != tt.want.Version || got.CompatibleVersion != tt.want.CompatibleVersion ||
+				got.ServiceOptions != tt.want.ServiceOptions || got.SDU != tt.want.SDU || got.TDU != tt.want.TDU ||
+				got.NTProtocolCharacteristics != tt.want.NTProtocolCharacteristics ||
+				got.ConnectFlags0 != tt.want.ConnectFlags0 || got.ConnectFlags1 != tt.want.ConnectFlags1 ||
+				got.ConnectData != tt.want.ConnectData {
+				t.Errorf("ReadConnectPacket() = \n%#v,\n want \n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadAcceptPacket(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+		want AcceptPacket
+	}{
+		{
+			name: "version 314",
+			b: []byte{
+				0x00, 0x20, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x01, 0x3a, 0x00, 0x01, 0x20, 0x00, 0xff, 0xff,
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x20, 0x41, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			want: AcceptPacket{
+				Version:        314,
+				ServiceOptions: 0x0001,
+				SDU:            8192,
+				TDU:            65535,
+				ConnectFlags0:  0x41,
+				ConnectFlags1:  0x01,
+			},
+		},
+		{
+			name: "version 318 with large SDU",
+			b: []byte{
+				0x00, 0x2d, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x01, 0x3e, 0x00, 0x01, 0xff, 0xff, 0xff, 0xff,
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x2d, 0x41, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x20, 0x00, 0x00, 0x00, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			want: AcceptPacket{
+				Version:        318,
+				ServiceOptions: 0x0001,
+				SDU:            2097152,
+				TDU:            2097152,
+				ConnectFlags0:  0x41,
+				ConnectFlags1:  0x01,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReadAcceptPacket(tt.b)
+			if got.PkType != Accept {
+				t.Errorf("PkType = %d, want %d", got.PkType, Accept)
+			}
+			if got.Version != tt.want.Version || got.ServiceOptions != tt.want.ServiceOptions ||
+				got.SDU != tt.want.SDU || got.TDU != tt.want.TDU ||
+				got.ConnectFlags0 != tt.want.ConnectFlags0 || got.ConnectFlags1 != tt.want.ConnectFlags1 {
+				t.Errorf("ReadAcceptPacket() = \n%#v,\n want \n%#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package packet
+
+import (
+	"bytes"
+)
+
+// Describe is the description of the columns of a query, sent by the server when the cursor is parsed
+type Describe struct {
+	MaxRowSize uint32
+	Columns    []Column
+}
+
+func (m Describe) Code() TTCCode { return TTIDCB }
+
+// Column describes a column of a query
+type Column struct {
+	Name        string
+	Schema      string // Schema of the column's type, for object types
+	TypeName    string // Name of the column's type, for object types
+	DataType    uint8
+	Flag        uint8
+	Precision   uint8
+	Scale       int16 // -127 for FLOAT numbers
+	MaxLen      uint32
+	MaxArrayLen uint32
+	ContFlag    uint64
+	ToID        []byte
+	Version     uint16
+	CharsetID   uint16
+	CharsetForm uint8 // 1 database character set, 2 national character set
+	MaxCharLen  uint32
+	Nullable    bool
+}
+
+// Data types with a special layout in describe and row data
+const (
+	typeNumber       = 2
+	typeRowId        = 11
+	typeClob         = 112
+	typeBlob         = 113
+	typeBFile        = 114
+	typeTimeStamp    = 180
+	typeTimeStampTZ  = 181
+	typeIntervalDS   = 183
+	typeTimeStampLTZ = 231
+)
+
+func (c TTCContext) readDescribe(buff *bytes.Buffer) (Describe, error) {
+	var err error
+	m := Describe{}
+
+	var l byte
+	l, err = buff.ReadByte()
+	if err != nil {
+		return m, err
+	}
+	err = checkLength(buff, int(l))
+	if err != nil {
+		return m, err
+	}
+	buff.Next(int(l))
+	m.MaxRowSize, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	var n uint32
+	n, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	if n > 0 {
+		_, err = buff.ReadByte() // Array flag
+		if err != nil {
+			return m, err
+		}
+	}
+	for i := 0; i < int(n); i++ {
+		var col Column
+		col, err = c.readColumn(buff)
+		if err != nil {
+			return m, err
+		}
+		m.Columns = append(m.Columns, col)
+	}
+
+	_, err = readDlc(buff)
+	if err != nil {
+		return m, err
+	}
+	if c.TTCVersion >= 3 {
+		err = SkipUInts(buff, 4, 4)
+		if err != nil {
+			return m, err
+		}
+	}
+	if c.TTCVersion >= 4 {
+		err = SkipUInts(buff, 4, 4)
+		if err != nil {
+			return m, err
+		}
+	}
+	if c.TTCVersion >= 5 {
+		_, err = readDlc(buff)
+	}
+	return m, err
+}
+
+func (c TTCContext) readColumn(buff *bytes.Buffer) (Column, error) {
+	var err error
+	col := Column{}
+
+	col.DataType, err = buff.ReadByte()
+	if err != nil {
+		return col, err
+	}
+	col.Flag, err = buff.ReadByte()
+	if err != nil {
+		return col, err
+	}
+	col.Precision, err = buff.ReadByte()
+	if err != nil {
+		return col, err
+	}
+	switch col.DataType {
+	case typeNumber, typeTimeStamp, typeTimeStampTZ, typeIntervalDS, typeTimeStampLTZ:
+		var scale int32
+		scale, err = GetInt(buff, 2, true, true)
+		col.Scale = int16(scale)
+	default:
+		var scale byte
+		scale, err = buff.ReadByte()
+		col.Scale = int16(scale)
+	}
+	if err != nil {
+		return col, err
+	}
+	col.MaxLen, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return col, err
+	}
+	col.MaxArrayLen, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return col, err
+	}
+	var flag int64
+	flag, err = GetInt64(buff, 8, true, true)
+	if err != nil {
+		return col, err
+	}
+	col.ContFlag = uint64(flag)
+	col.ToID, err = readDlc(buff)
+	if err != nil {
+		return col, err
+	}
+	var n uint32
+	n, err = GetUInt(buff, 2, true, true)
+	if err != nil {
+		return col, err
+	}
+	col.Version = uint16(n)
+	n, err = GetUInt(buff, 2, true, true)
+	if err != nil {
+		return col, err
+	}
+	col.CharsetID = uint16(n)
+	col.CharsetForm, err = buff.ReadByte()
+	if err != nil {
+		return col, err
+	}
+	col.MaxCharLen, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return col, err
+	}
+	if c.TTCVersion >= 8 {
+		err = SkipUInts(buff, 4) // Collation id
+		if err != nil {
+			return col, err
+		}
+	}
+	var b byte
+	b, err = buff.ReadByte()
+	if err != nil {
+		return col, err
+	}
+	col.Nullable = b > 0
+	_, err = buff.ReadByte() // V7 length of the name
+	if err != nil {
+		return col, err
+	}
+	for _, s := range []*string{&col.Name, &col.Schema, &col.TypeName} {
+		var v []byte
+		v, err = readDlc(buff)
+		if err != nil {
+			return col, err
+		}
+		*s = string(v)
+	}
+	if c.TTCVersion >= 3 {
+		err = SkipUInts(buff, 2) // Column position
+		if err != nil {
+			return col, err
+		}
+	}
+	if c.TTCVersion >= 6 {
+		err = SkipUInts(buff, 4) // UDS flags
+		if err != nil {
+			return col, err
+		}
+	}
+	if c.TTCVersion >= 17 {
+		// Domain schema and name
+		for i := 0; i < 2; i++ {
+			_, err = readDlc(buff)
+			if err != nil {
+				return col, err
+			}
+		}
+	}
+	if c.TTCVersion >= 20 {
+		// Annotations
+		n, err = GetUInt(buff, 4, true, true)
+		if err != nil || n == 0 {
+			return col, err
+		}
+		_, err = buff.ReadByte()
+		if err != nil {
+			return col, err
+		}
+		for i := 0; i < int(n); i++ {
+			_, _, _, err = readKeyVal(buff)
+			if err != nil {
+				return col, err
+			}
+		}
+		err = SkipUInts(buff, 4)
+	}
+	return col, err
+}
@@ -0,0 +1,37 @@
+package packet
+
+import (
+	"strings"
+)
+
+// DescriptorSection returns the first (KEY=...) section of a descriptor, parenthesis included.
+// The key is case insensitive. An empty string is returned when the key isn't found
+func DescriptorSection(d string, key string) string {
+	start := strings.Index(strings.ToUpper(d), "("+strings.ToUpper(key)+"=")
+	if start < 0 {
+		return ""
+	}
+	depth := 0
+	for i := start; i < len(d); i++ {
+		switch d[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return d[start : i+1]
+			}
+		}
+	}
+	return d[start:]
+}
+
+// DescriptorValue returns the value of the first (KEY=value) of a descriptor
+func DescriptorValue(d string, key string) string {
+	s := DescriptorSection(d, key)
+	if s == "" {
+		return ""
+	}
+	s = strings.TrimSuffix(s[len(key)+2:], ")")
+	return strings.TrimSpace(s)
+}
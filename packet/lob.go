@@ -0,0 +1,274 @@
+package packet
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// LobOperation is the operation of an OLOBOPS call
+type LobOperation uint32
+
+const (
+	LobGetLength       LobOperation = 0x00001
+	LobRead            LobOperation = 0x00002
+	LobTrim            LobOperation = 0x00020
+	LobWrite           LobOperation = 0x00040
+	LobCreateTemporary LobOperation = 0x00110
+	LobFreeTemporary   LobOperation = 0x00111
+	LobGetChunkSize    LobOperation = 0x04000
+	LobOpen            LobOperation = 0x08000
+	LobClose           LobOperation = 0x10000
+	LobIsOpen          LobOperation = 0x11000
+)
+
+var lobOperationStr = map[LobOperation]string{
+	LobGetLength:       "get length",
+	LobRead:            "read",
+	LobTrim:            "trim",
+	LobWrite:           "write",
+	LobCreateTemporary: "create temporary",
+	LobFreeTemporary:   "free temporary",
+	LobGetChunkSize:    "get chunk size",
+	LobOpen:            "open",
+	LobClose:           "close",
+	LobIsOpen:          "is open",
+}
+
+func (o LobOperation) String() string {
+	if s, ok := lobOperationStr[o]; ok {
+		return s
+	}
+	return fmt.Sprintf("LobOperation(%x)", uint32(o))
+}
+
+// LobCall is the client's OLOBOPS call, an operation on a LOB locator
+type LobCall struct {
+	Sequence     uint8
+	Operation    LobOperation
+	Source       []byte // Source locator
+	Dest         []byte // Destination locator
+	SourceOffset uint64 // Offset in the source LOB, from 1
+	DestOffset   uint64 // Offset in the destination LOB, from 1
+	Charset      uint16 // Character set of CLOB data, 0 when not sent
+	SendSize     bool   // The size is sent and returned
+	Size         uint64 // Amount to read or write, new length for trim
+	NullFlag     bool   // The server returns a boolean, like for is open
+}
+
+func (m LobCall) Code() TTCCode { return TTIFUN }
+
+// Call gives the LOB function call, without its body
+func (m LobCall) Call() FunctionCall {
+	return FunctionCall{Function: OLOBOPS, Sequence: m.Sequence}
+}
+
+// readLobCall decodes the call, its layout depends on the TTC version
+func (c TTCContext) readLobCall(buff *bytes.Buffer, call FunctionCall) (LobCall, error) {
+	var err error
+	m := LobCall{Sequence: call.Sequence}
+
+	var srcLen, dstLen, n uint32
+	var b byte
+	err = SkipUInts(buff, 1) // Source locator pointer
+	if err != nil {
+		return m, err
+	}
+	srcLen, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	err = SkipUInts(buff, 1) // Destination locator pointer
+	if err != nil {
+		return m, err
+	}
+	dstLen, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	if c.TTCVersion < 3 {
+		n, err = GetUInt(buff, 4, true, true)
+		if err != nil {
+			return m, err
+		}
+		m.SourceOffset = uint64(n)
+		n, err = GetUInt(buff, 4, true, true)
+		if err != nil {
+			return m, err
+		}
+		m.DestOffset = uint64(n)
+	} else {
+		err = SkipUInts(buff, 4, 4) // Short offsets, replaced by the long ones below
+		if err != nil {
+			return m, err
+		}
+	}
+	b, err = buff.ReadByte() // Charset pointer
+	if err != nil {
+		return m, err
+	}
+	charset := b > 0
+	b, err = buff.ReadByte() // Size pointer, before TTC version 3
+	if err != nil {
+		return m, err
+	}
+	m.SendSize = b > 0
+	b, err = buff.ReadByte()
+	if err != nil {
+		return m, err
+	}
+	m.NullFlag = b > 0
+	n, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Operation = LobOperation(n)
+	err = SkipUInts(buff, 1) // SCN pointer
+	if err != nil {
+		return m, err
+	}
+	var scn uint32
+	scn, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	if c.TTCVersion >= 3 {
+		var o int64
+		o, err = GetInt64(buff, 8, true, true)
+		if err != nil {
+			return m, err
+		}
+		m.SourceOffset = uint64(o)
+		o, err = GetInt64(buff, 8, true, true)
+		if err != nil {
+			return m, err
+		}
+		m.DestOffset = uint64(o)
+		b, err = buff.ReadByte() // Size pointer
+		if err != nil {
+			return m, err
+		}
+		m.SendSize = b > 0
+	}
+	if c.TTCVersion >= 4 {
+		err = SkipUInts(buff, 1, 1, 1, 1, 1, 1)
+		if err != nil {
+			return m, err
+		}
+	}
+
+	m.Source, err = readLocator(buff, int(srcLen))
+	if err != nil {
+		return m, err
+	}
+	m.Dest, err = readLocator(buff, int(dstLen))
+	if err != nil {
+		return m, err
+	}
+	if charset {
+		n, err = GetUInt(buff, 2, true, true)
+		if err != nil {
+			return m, err
+		}
+		m.Charset = uint16(n)
+	}
+	if m.SendSize && c.TTCVersion < 3 {
+		n, err = GetUInt(buff, 4, true, true)
+		if err != nil {
+			return m, err
+		}
+		m.Size = uint64(n)
+	}
+	for i := 0; i < int(scn); i++ {
+		err = SkipUInts(buff, 4)
+		if err != nil {
+			return m, err
+		}
+	}
+	if m.SendSize && c.TTCVersion >= 3 {
+		var s int64
+		s, err = GetInt64(buff, 8, true, true)
+		if err != nil {
+			return m, err
+		}
+		m.Size = uint64(s)
+	}
+	return m, nil
+}
+
+// readLocator reads a locator of the given length, nil when there is none
+func readLocator(buff *bytes.Buffer, l int) ([]byte, error) {
+	if l == 0 {
+		return nil, nil
+	}
+	err := checkLength(buff, l)
+	if err != nil {
+		return nil, err
+	}
+	return buff.Next(l), nil
+}
+
+// LobData holds data written by the client after an OLOBOPS call, or read from the server
+type LobData struct {
+	Data []byte
+}
+
+func (m LobData) Code() TTCCode { return TTILOBD }
+
+func readLobData(buff *bytes.Buffer) (LobData, error) {
+	data, err := ReadBytes(buff)
+	return LobData{Data: data}, err
+}
+
+// LobParameters are returned by the server for an OLOBOPS call, the layout follows the call
+type LobParameters struct {
+	Source  []byte // Source locator, updated by the operation
+	Dest    []byte // Destination locator
+	Charset uint16
+	Size    uint64 // LOB length, or amount read or written
+	Flag    bool   // Answer of operations like is open
+}
+
+func (m LobParameters) Code() TTCCode { return TTIRPA }
+
+func (c TTCContext) readLobParameters(buff *bytes.Buffer) (LobParameters, error) {
+	var err error
+	m := LobParameters{}
+	call := c.LobCall
+
+	m.Source, err = readLocator(buff, len(call.Source))
+	if err != nil {
+		return m, err
+	}
+	m.Dest, err = readLocator(buff, len(call.Dest))
+	if err != nil {
+		return m, err
+	}
+	if call.Charset != 0 {
+		var n uint32
+		n, err = GetUInt(buff, 2, true, true)
+		if err != nil {
+			return m, err
+		}
+		m.Charset = uint16(n)
+	}
+	if call.SendSize {
+		if c.TTCVersion < 3 {
+			var n uint32
+			n, err = GetUInt(buff, 4, true, true)
+			m.Size = uint64(n)
+		} else {
+			var n int64
+			n, err = GetInt64(buff, 8, true, true)
+			m.Size = uint64(n)
+		}
+		if err != nil {
+			return m, err
+		}
+	}
+	if call.NullFlag {
+		var b byte
+		b, err = buff.ReadByte()
+		m.Flag = b > 0
+	}
+	return m, err
+}
@@ -0,0 +1,99 @@
+package packet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadLobMessages(t *testing.T) {
+	locator := []byte{
+		0x00, 0x26, 0x00, 0x04, 0x00, 0x01, 0x00, 0x01, 0x00, 0x01, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46,
+		0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f, 0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56,
+		0x57, 0x58, 0x59, 0x5a, 0x5b, 0x5c, 0x5d, 0x5e,
+	}
+	call := LobCall{
+		Sequence:     4,
+		Operation:    LobWrite,
+		Source:       locator,
+		SourceOffset: 1,
+		Charset:      2000,
+		SendSize:     true,
+		Size:         5,
+	}
+	tests := []struct {
+		name string
+		ctx  TTCContext
+		b    []byte
+		want []TTCMessage
+	}{
+		{
+			name: "write call and its data",
+			ctx:  TTCContext{TTCVersion: 11},
+			b: []byte{
+				0x00, 0x5e, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x60, 0x04, 0x01, 0x01, 0x28,
+				0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x01, 0x40, 0x00, 0x00, 0x01, 0x01, 0x00, 0x01, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x26, 0x00, 0x04, 0x00, 0x01, 0x00, 0x01, 0x00, 0x01, 0x41,
+				0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f, 0x50, 0x51,
+				0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x5b, 0x5c, 0x5d, 0x5e, 0x02, 0x07, 0xd0,
+				0x01, 0x05, 0x0e, 0x0a, 0x00, 0x68, 0x00, 0xe9, 0x00, 0x6c, 0x00, 0x6c, 0x00, 0x6f,
+			},
+			want: []TTCMessage{
+				call,
+				LobData{Data: []byte{0x00, 0x68, 0x00, 0xe9, 0x00, 0x6c, 0x00, 0x6c, 0x00, 0x6f}},
+			},
+		},
+		{
+			name: "write call with short offsets",
+			ctx:  TTCContext{TTCVersion: 11},
+			b: []byte{
+				0x00, 0x60, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x60, 0x04, 0x01, 0x01, 0x28,
+				0x00, 0x00, 0x01, 0x01, 0x01, 0x01, 0x01, 0x00, 0x00, 0x01, 0x40, 0x00, 0x00, 0x01, 0x01, 0x00,
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x26, 0x00, 0x04, 0x00, 0x01, 0x00, 0x01, 0x00,
+				0x01, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f,
+				0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x5b, 0x5c, 0x5d, 0x5e, 0x02,
+				0x07, 0xd0, 0x01, 0x05, 0x0e, 0x0a, 0x00, 0x68, 0x00, 0xe9, 0x00, 0x6c, 0x00, 0x6c, 0x00, 0x6f,
+			},
+			want: []TTCMessage{
+				call,
+				LobData{Data: []byte{0x00, 0x68, 0x00, 0xe9, 0x00, 0x6c, 0x00, 0x6c, 0x00, 0x6f}},
+			},
+		},
+		{
+			name: "write response",
+			ctx:  TTCContext{FromServer: true, Function: OLOBOPS, TTCVersion: 11, LobCall: call},
+			b: []byte{
+				0x00, 0x57, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x26, 0x00, 0x04, 0x00,
+				0x01, 0x00, 0x01, 0x00, 0x01, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0x4a, 0x4b,
+				0x4c, 0x4d, 0x4e, 0x4f, 0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0x5b,
+				0x5c, 0x5d, 0x5e, 0x02, 0x07, 0xd0, 0x01, 0x05, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x09, 0x00, 0x01, 0x05,
+			},
+			want: []TTCMessage{
+				LobParameters{Source: locator, Charset: 2000, Size: 5},
+				Summary{},
+				Status{Sequence: 5},
+			},
+		},
+		{
+			name: "unknown TTC version",
+			ctx:  TTCContext{},
+			b:    []byte{0x00, 0x11, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x60, 0x04, 0x01, 0x01, 0x28, 0x00},
+			want: []TTCMessage{
+				FunctionCall{Function: OLOBOPS, Sequence: 4, Body: []byte{0x01, 0x01, 0x28, 0x00}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ctx.ReadMessages(tt.b)
+			if err != nil {
+				t.Errorf("ReadMessages() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadMessages() = \n%#v,\n want \n%#v", got, tt.want)
+			}
+		})
+	}
+}
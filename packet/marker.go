@@ -0,0 +1,64 @@
+package packet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MarkerKind is the meaning of a marker
+type MarkerKind uint8
+
+const (
+	BreakMarker     MarkerKind = 1 // Stop the current call
+	ResetMarker     MarkerKind = 2 // Acknowledge a break, the connection is usable again
+	InterruptMarker MarkerKind = 3 // Interrupt the current call
+)
+
+var markerKindStr = map[MarkerKind]string{
+	BreakMarker:     "break",
+	ResetMarker:     "reset",
+	InterruptMarker: "interrupt",
+}
+
+func (k MarkerKind) String() string {
+	if s, ok := markerKindStr[k]; ok {
+		return s
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(k))
+}
+
+// MarkerPacket is sent by the client or the server to break the current call.
+// Attention packets have the same layout
+type MarkerPacket struct {
+	TNSHeader             // ofs: 0 Len 8
+	MarkerType uint8      // ofs: 8, 0 or 1: data marker, 2: attention marker
+	Kind       MarkerKind // ofs: 10
+}
+
+func ReadMarkerPacket(b []byte, version uint16) MarkerPacket {
+	return MarkerPacket{
+		TNSHeader:  ReadTNSHeaderVersion(b, version),
+		MarkerType: getUint8(b, 8),
+		Kind:       MarkerKind(getUint8(b, 10)),
+	}
+}
+
+func (mp MarkerPacket) String() string {
+	sb := strings.Builder{}
+	mp.StringBuilder(&sb)
+	return sb.String()
+}
+
+func (mp MarkerPacket) StringBuilder(sb *strings.Builder) {
+	mp.TNSHeader.writeFields(sb)
+	writeEol(sb)
+	mp.writeFields(sb)
+	writeEol(sb)
+	writePayload(sb, mp.TNSHeader.Buffer)
+}
+
+func (mp MarkerPacket) writeFields(sb *strings.Builder) {
+	sb.WriteString("Marker packet fields: ")
+	sb.WriteString(fmt.Sprintf("MarkerType(%d),", mp.MarkerType))
+	sb.WriteString(fmt.Sprintf("Marker(%d=%s)", uint8(mp.Kind), mp.Kind))
+}
@@ -0,0 +1,79 @@
+package packet
+
+// errorMessages is a catalog of common ORA and TNS messages, used when only the code is known.
+// Variable parts of the messages are left out.
+var errorMessages = map[int]string{
+	1:     "unique constraint violated",
+	54:    "resource busy and acquire with NOWAIT specified or timeout expired",
+	60:    "deadlock detected while waiting for resource",
+	900:   "invalid SQL statement",
+	904:   "invalid identifier",
+	907:   "missing right parenthesis",
+	911:   "invalid character",
+	917:   "missing comma",
+	918:   "column ambiguously defined",
+	920:   "invalid relational operator",
+	921:   "unexpected end of SQL command",
+	923:   "FROM keyword not found where expected",
+	933:   "SQL command not properly ended",
+	936:   "missing expression",
+	937:   "not a single-group group function",
+	942:   "table or view does not exist",
+	947:   "not enough values",
+	955:   "name is already used by an existing object",
+	979:   "not a GROUP BY expression",
+	1000:  "maximum open cursors exceeded",
+	1002:  "fetch out of sequence",
+	1008:  "not all variables bound",
+	1012:  "not logged on",
+	1013:  "user requested cancel of current operation",
+	1017:  "invalid username/password; logon denied",
+	1400:  "cannot insert NULL",
+	1403:  "no data found",
+	1405:  "fetched column value is NULL",
+	1407:  "cannot update to NULL",
+	1422:  "exact fetch returns more than requested number of rows",
+	1427:  "single-row subquery returns more than one row",
+	1438:  "value larger than specified precision allowed for this column",
+	1476:  "divisor is equal to zero",
+	1555:  "snapshot too old",
+	1722:  "invalid number",
+	1756:  "quoted string not properly terminated",
+	1830:  "date format picture ends before converting entire input string",
+	1843:  "not a valid month",
+	1861:  "literal does not match format string",
+	2049:  "timeout: distributed transaction waiting for lock",
+	2291:  "integrity constraint violated - parent key not found",
+	2292:  "integrity constraint violated - child record found",
+	3113:  "end-of-file on communication channel",
+	3114:  "not connected to ORACLE",
+	3135:  "connection lost contact",
+	4031:  "unable to allocate shared memory",
+	4068:  "existing state of packages has been discarded",
+	6502:  "PL/SQL: numeric or value error",
+	6508:  "PL/SQL: could not find program unit being called",
+	6512:  "at line",
+	6550:  "PL/SQL compilation error",
+	12154: "TNS:could not resolve the connect identifier specified",
+	12170: "TNS:Connect timeout occurred",
+	12500: "TNS:listener failed to start a dedicated server process",
+	12505: "TNS:listener does not currently know of SID given in connect descriptor",
+	12514: "TNS:listener does not currently know of service requested in connect descriptor",
+	12516: "TNS:listener could not find available handler with matching protocol stack",
+	12518: "TNS:listener could not hand off client connection",
+	12519: "TNS:no appropriate service handler found",
+	12520: "TNS:listener could not find available handler for requested type of server",
+	12528: "TNS:listener: all appropriate instances are blocking new connections",
+	12537: "TNS:connection closed",
+	12541: "TNS:no listener",
+	12543: "TNS:destination host unreachable",
+	12545: "Connect failed because target host or object does not exist",
+	12560: "TNS:protocol adapter error",
+	28000: "the account is locked",
+	28001: "the password has expired",
+}
+
+// ErrorMessage gives the message of common ORA and TNS errors, an empty string for other codes
+func ErrorMessage(code int) string {
+	return errorMessages[code]
+}
@@ -0,0 +1,309 @@
+package packet
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ProtocolNegotiation is the first TTC message exchanged by the client and the server
+type ProtocolNegotiation struct {
+	FromServer      bool
+	Versions        []uint8 // Protocol versions accepted by the client, or the one chosen by the server
+	Banner          string  // Platform of the sender, like IBMPC/WIN_NT-8.1.0 or x86_64/Linux 2.4.xx
+	Charset         uint16  // Database character set, server only
+	Flags           uint8   // Server flags
+	NCharset        uint16  // National character set, server only
+	CompileTimeCaps []byte  // Server compile time capabilities
+	RuntimeCaps     []byte  // Server runtime capabilities
+}
+
+func (m ProtocolNegotiation) Code() TTCCode { return TTIPRO }
+
+// Version gives the protocol version chosen by the server, the highest accepted by the client otherwise
+func (m ProtocolNegotiation) Version() uint8 {
+	if len(m.Versions) == 0 {
+		return 0
+	}
+	return m.Versions[0]
+}
+
+func readProtocolNegotiation(buff *bytes.Buffer, fromServer bool) (ProtocolNegotiation, error) {
+	var err error
+	m := ProtocolNegotiation{FromServer: fromServer}
+
+	if !fromServer {
+		// Accepted versions, ended by 0, then the client's platform
+		var versions []byte
+		versions, err = readNullTerminated(buff)
+		if err != nil {
+			return m, err
+		}
+		m.Versions = versions
+		var banner []byte
+		banner, err = readNullTerminated(buff)
+		m.Banner = string(banner)
+		return m, err
+	}
+
+	var b byte
+	b, err = buff.ReadByte()
+	if err != nil {
+		return m, err
+	}
+	m.Versions = []uint8{b}
+	_, err = buff.ReadByte()
+	if err != nil {
+		return m, err
+	}
+	var banner []byte
+	banner, err = readNullTerminated(buff)
+	if err != nil {
+		return m, err
+	}
+	m.Banner = string(banner)
+
+	var n uint32
+	n, err = GetUInt(buff, 2, false, false)
+	if err != nil {
+		return m, err
+	}
+	m.Charset = uint16(n)
+	m.Flags, err = buff.ReadByte()
+	if err != nil {
+		return m, err
+	}
+
+	// Character set elements
+	n, err = GetUInt(buff, 2, false, false)
+	if err != nil {
+		return m, err
+	}
+	err = checkLength(buff, int(n)*5)
+	if err != nil {
+		return m, err
+	}
+	buff.Next(int(n) * 5)
+	if buff.Len() == 0 {
+		// Old servers stop here
+		return m, nil
+	}
+
+	// FDO, the national character set is inside
+	n, err = GetUInt(buff, 2, false, true)
+	if err != nil {
+		return m, err
+	}
+	err = checkLength(buff, int(n))
+	if err != nil {
+		return m, err
+	}
+	fdo := buff.Next(int(n))
+	if len(fdo) > 6 {
+		ofs := 6 + int(fdo[5]) + int(fdo[6]) + 3
+		if ofs+2 <= len(fdo) {
+			m.NCharset = uint16(fdo[ofs])<<8 | uint16(fdo[ofs+1])
+		}
+	}
+
+	m.CompileTimeCaps, err = readDlc(buff)
+	if err != nil {
+		return m, err
+	}
+	m.RuntimeCaps, err = readDlc(buff)
+	return m, err
+}
+
+// ServerVersion is the server's answer to the OVERSION call
+type ServerVersion struct {
+	Banner string // Like Oracle Database 11g Enterprise Edition Release 11.2.0.4.0 - 64bit Production
+	Number uint32 // Version number, one digit per nibble or byte
+}
+
+func (m ServerVersion) Code() TTCCode { return TTIRPA }
+
+// Version gives the version number in its usual form, like 11.2.0.4.0
+func (m ServerVersion) Version() string {
+	n := m.Number
+	return fmt.Sprintf("%d.%d.%d.%d.%d", n>>24&0xFF, n>>20&0x0F, n>>12&0x0F, n>>8&0x0F, n&0xFF)
+}
+
+func readServerVersion(buff *bytes.Buffer) (ServerVersion, error) {
+	m := ServerVersion{}
+
+	l, err := GetUInt(buff, 2, true, true)
+	if err != nil {
+		return m, err
+	}
+	var banner []byte
+	banner, err = ReadBytes(buff)
+	if err != nil {
+		return m, err
+	}
+	if len(banner) > int(l) {
+		banner = banner[:l]
+	}
+	m.Banner = string(banner)
+	m.Number, err = GetUInt(buff, 4, true, true)
+	return m, err
+}
+
+// readNullTerminated reads bytes up to the next 0, the 0 is discarded
+func readNullTerminated(buff *bytes.Buffer) ([]byte, error) {
+	b, err := buff.ReadBytes(0)
+	if err != nil {
+		return nil, err
+	}
+	return b[:len(b)-1], nil
+}
+
+var charsetStr = map[uint16]string{
+	1:    "US7ASCII",
+	31:   "WE8ISO8859P1",
+	46:   "WE8ISO8859P15",
+	170:  "EE8MSWIN1250",
+	171:  "CL8MSWIN1251",
+	178:  "WE8MSWIN1252",
+	871:  "UTF8",
+	873:  "AL32UTF8",
+	2000: "AL16UTF16",
+}
+
+// CharsetName gives the name of usual character sets, the id otherwise
+func CharsetName(id uint16) string {
+	if s, ok := charsetStr[id]; ok {
+		return s
+	}
+	return fmt.Sprintf("charset(%d)", id)
+}
+
+// DataTypeNegotiation sets the capabilities and the representation of data types.
+// The client proposes its table, the server answers with the accepted one.
+type DataTypeNegotiation struct {
+	FromServer      bool
+	Charset         uint16               // Client only
+	NCharset        uint16               // Client only
+	Flags           uint8                // Client only
+	CompileTimeCaps []byte               // Client's compile time capabilities
+	RuntimeCaps     []byte               // Client's runtime capabilities
+	Types           []TypeRepresentation // Type conversion table
+}
+
+// TypeRepresentation tells how a data type is sent on the wire
+type TypeRepresentation struct {
+	Type           uint16 // Data type
+	ConvType       uint16 // Type actually sent, 0 when the type isn't supported
+	Representation uint16 // 1 native, 10 universal
+}
+
+func (m DataTypeNegotiation) Code() TTCCode { return TTIDTY }
+
+// hasTimeZone checks the capabilities for the time zone information exchanged before the types table
+func hasTimeZone(compileTimeCaps, runtimeCaps []byte) (bool, bool) {
+	tz := len(runtimeCaps) > 1 && runtimeCaps[1]&1 == 1
+	version := tz && len(compileTimeCaps) > 37 && compileTimeCaps[37]&2 == 2
+	return tz, version
+}
+
+// typeWidth gives the size of numbers in the types table
+func typeWidth(compileTimeCaps []byte) int {
+	if len(compileTimeCaps) > 27 && compileTimeCaps[27] == 0 {
+		return 1
+	}
+	return 2
+}
+
+func readClientDataTypes(buff *bytes.Buffer) (DataTypeNegotiation, error) {
+	var err error
+	var n uint32
+	m := DataTypeNegotiation{}
+
+	n, err = GetUInt(buff, 2, false, false)
+	if err != nil {
+		return m, err
+	}
+	m.Charset = uint16(n)
+	n, err = GetUInt(buff, 2, false, false)
+	if err != nil {
+		return m, err
+	}
+	m.NCharset = uint16(n)
+	m.Flags, err = buff.ReadByte()
+	if err != nil {
+		return m, err
+	}
+	for _, caps := range []*[]byte{&m.CompileTimeCaps, &m.RuntimeCaps} {
+		var l byte
+		l, err = buff.ReadByte()
+		if err != nil {
+			return m, err
+		}
+		err = checkLength(buff, int(l))
+		if err != nil {
+			return m, err
+		}
+		*caps = buff.Next(int(l))
+	}
+
+	tz, tzVersion := hasTimeZone(m.CompileTimeCaps, m.RuntimeCaps)
+	if tz {
+		buff.Next(11) // Client's time zone
+	}
+	if tzVersion {
+		buff.Next(4) // Client's time zone file version
+	}
+	_, err = GetUInt(buff, 2, false, false) // National character set, again
+	if err != nil {
+		return m, err
+	}
+
+	m.Types, err = readTypeRepresentations(buff, typeWidth(m.CompileTimeCaps))
+	return m, err
+}
+
+func (c TTCContext) readServerDataTypes(buff *bytes.Buffer) (DataTypeNegotiation, error) {
+	var err error
+	m := DataTypeNegotiation{
+		FromServer:      true,
+		CompileTimeCaps: c.CompileTimeCaps,
+		RuntimeCaps:     c.RuntimeCaps,
+	}
+	if _, tzVersion := hasTimeZone(c.CompileTimeCaps, c.RuntimeCaps); tzVersion {
+		buff.Next(4) // Server's time zone file version
+	}
+	m.Types, err = readTypeRepresentations(buff, typeWidth(c.CompileTimeCaps))
+	return m, err
+}
+
+// readTypeRepresentations reads the types table.
+// Entries are type, conversion type, representation and 0, or type and 0 when the type isn't supported.
+// The table ends with 0.
+func readTypeRepresentations(buff *bytes.Buffer, width int) ([]TypeRepresentation, error) {
+	types := []TypeRepresentation{}
+	level := 0
+	t := TypeRepresentation{}
+	for {
+		v, err := GetUInt(buff, width, false, true)
+		if err != nil {
+			return types, err
+		}
+		switch {
+		case level == 0 && v == 0:
+			return types, nil
+		case level == 0:
+			t = TypeRepresentation{Type: uint16(v)}
+			level++
+		case level == 1 && v == 0:
+			types = append(types, t)
+			level = 0
+		case level == 1:
+			t.ConvType = uint16(v)
+			level++
+		case level == 2:
+			t.Representation = uint16(v)
+			level++
+		default:
+			types = append(types, t)
+			level = 0
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package packet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadProtocolNegotiation(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  TTCContext
+		b    []byte
+		want []TTCMessage
+	}{
+		{
+			name: "client",
+			b: []byte{
+				0x00, 0x25, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x06, 0x05, 0x04, 0x03, 0x02,
+				0x01, 0x00, 0x49, 0x42, 0x4d, 0x50, 0x43, 0x2f, 0x57, 0x49, 0x4e, 0x5f, 0x4e, 0x54, 0x2d, 0x38,
+				0x2e, 0x31, 0x2e, 0x30, 0x00,
+			},
+			want: []TTCMessage{
+				ProtocolNegotiation{
+					Versions: []uint8{6, 5, 4, 3, 2, 1},
+					Banner:   "IBMPC/WIN_NT-8.1.0",
+				},
+			},
+		},
+		{
+			name: "server",
+			ctx:  TTCContext{FromServer: true},
+			b: []byte{
+				0x00, 0x48, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x06, 0x00, 0x78, 0x38, 0x36,
+				0x5f, 0x36, 0x34, 0x2f, 0x4c, 0x69, 0x6e, 0x75, 0x78, 0x20, 0x32, 0x2e, 0x34, 0x2e, 0x78, 0x78,
+				0x00, 0x69, 0x03, 0x01, 0x00, 0x00, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x07, 0xd0, 0x01, 0x08, 0x08, 0x06, 0x01, 0x00, 0x00, 0x6a, 0x01, 0x01, 0x0b, 0x01, 0x07,
+				0x07, 0x02, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			want: []TTCMessage{
+				ProtocolNegotiation{
+					FromServer:      true,
+					Versions:        []uint8{6},
+					Banner:          "x86_64/Linux 2.4.xx",
+					Charset:         873,
+					Flags:           1,
+					NCharset:        2000,
+					CompileTimeCaps: []byte{0x06, 0x01, 0x00, 0x00, 0x6a, 0x01, 0x01, 0x0b},
+					RuntimeCaps:     []byte{0x02, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00},
+				},
+			},
+		},
+		{
+			name: "server version call after an unknown piggyback",
+			b: []byte{
+				0x00, 0x1e, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x11, 0x6b, 0x04, 0x02, 0x03, 0x68,
+				0x02, 0x05, 0xd1, 0x01, 0x01, 0x03, 0x3b, 0x05, 0x01, 0x02, 0x03, 0x00, 0x01, 0x01, 0x00, 0x00,
+				0x00,
+			},
+			want: []TTCMessage{
+				Piggyback{Function: 0x6b, Sequence: 0x04, Body: []byte{0x02, 0x03, 0x68, 0x02, 0x05, 0xd1, 0x01, 0x01}},
+				FunctionCall{Function: OVERSION, Sequence: 0x05, Body: []byte{0x01, 0x02, 0x03, 0x00, 0x01, 0x01, 0x00, 0x00, 0x00}},
+			},
+		},
+		{
+			name: "server version",
+			ctx:  TTCContext{FromServer: true, Function: OVERSION},
+			b: []byte{
+				0x00, 0x64, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x01, 0x4c, 0x4c, 0x4f, 0x72,
+				0x61, 0x63, 0x6c, 0x65, 0x20, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x20, 0x31, 0x31,
+				0x67, 0x20, 0x45, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x72, 0x69, 0x73, 0x65, 0x20, 0x45, 0x64, 0x69,
+				0x74, 0x69, 0x6f, 0x6e, 0x20, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x20, 0x31, 0x31, 0x2e,
+				0x32, 0x2e, 0x30, 0x2e, 0x34, 0x2e, 0x30, 0x20, 0x2d, 0x20, 0x36, 0x34, 0x62, 0x69, 0x74, 0x20,
+				0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x04, 0x0b, 0x20, 0x04, 0x00, 0x09,
+				0x01, 0x01, 0x01, 0x03,
+			},
+			want: []TTCMessage{
+				ServerVersion{
+					Banner: "Oracle Database 11g Enterprise Edition Release 11.2.0.4.0 - 64bit Production",
+					Number: 0x0b200400,
+				},
+				Status{CallStatus: 1, Sequence: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ctx.ReadMessages(tt.b)
+			if err != nil {
+				t.Errorf("ReadMessages() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadMessages() = \n%#v,\n want \n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerVersion_Version(t *testing.T) {
+	if got := (ServerVersion{Number: 0x0b200400}).Version(); got != "11.2.0.4.0" {
+		t.Errorf("Version() = %s, want 11.2.0.4.0", got)
+	}
+}
+
+func TestReadDataTypeNegotiation(t *testing.T) {
+	compileTimeCaps := []byte{6, 1, 0, 0, 106, 1, 1, 11, 1, 1, 1, 1, 1, 1, 0, 41, 144, 3, 7, 3, 0, 1, 0, 235, 1, 0, 5, 1, 0, 0, 0, 24, 0, 0, 7, 32, 2, 58, 0, 0, 5, 0, 0, 0, 8}
+	runtimeCaps := []byte{2, 1, 0, 0, 0, 0, 0}
+	tests := []struct {
+		name string
+		ctx  TTCContext
+		b    []byte
+		want []TTCMessage
+	}{
+		{
+			name: "client",
+			b: []byte{
+				0x00, 0x7d, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x69, 0x03, 0xd0, 0x07, 0x01,
+				0x2d, 0x06, 0x01, 0x00, 0x00, 0x6a, 0x01, 0x01, 0x0b, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x00,
+				0x29, 0x90, 0x03, 0x07, 0x03, 0x00, 0x01, 0x00, 0xeb, 0x01, 0x00, 0x05, 0x01, 0x00, 0x00, 0x00,
+				0x18, 0x00, 0x00, 0x07, 0x20, 0x02, 0x3a, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x08, 0x07, 0x02,
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80, 0x00, 0x00, 0x00, 0x3c, 0x3c, 0x3c, 0x80, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x20, 0xd0, 0x07, 0x00, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x02, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x44, 0x00, 0x02, 0x00, 0x0a, 0x00, 0x00, 0x00,
+				0x60, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x0c, 0x00, 0x00, 0x00, 0x00,
+			},
+			want: []TTCMessage{
+				DataTypeNegotiation{
+					Charset:         873,
+					NCharset:        2000,
+					Flags:           1,
+					CompileTimeCaps: compileTimeCaps,
+					RuntimeCaps:     runtimeCaps,
+					Types: []TypeRepresentation{
+						{Type: 1, ConvType: 1, Representation: 1},
+						{Type: 2, ConvType: 2, Representation: 10},
+						{Type: 68, ConvType: 2, Representation: 10},
+						{Type: 96, ConvType: 1, Representation: 1},
+						{Type: 12},
+					},
+				},
+			},
+		},
+		{
+			name: "server",
+			ctx:  TTCContext{FromServer: true, CompileTimeCaps: compileTimeCaps, RuntimeCaps: runtimeCaps},
+			b: []byte{
+				0x00, 0x39, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x20, 0x00,
+				0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x02, 0x00, 0x0a, 0x00, 0x00, 0x00,
+				0x44, 0x00, 0x02, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x60, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00,
+				0x0c, 0x00, 0x0c, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x00,
+			},
+			want: []TTCMessage{
+				DataTypeNegotiation{
+					FromServer:      true,
+					CompileTimeCaps: compileTimeCaps,
+					RuntimeCaps:     runtimeCaps,
+					Types: []TypeRepresentation{
+						{Type: 1, ConvType: 1, Representation: 1},
+						{Type: 2, ConvType: 2, Representation: 10},
+						{Type: 68, ConvType: 2, Representation: 10},
+						{Type: 96, ConvType: 1, Representation: 1},
+						{Type: 12, ConvType: 12, Representation: 10},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ctx.ReadMessages(tt.b)
+			if err != nil {
+				t.Errorf("ReadMessages() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadMessages() = \n%#v,\n want \n%#v", got, tt.want)
+			}
+		})
+	}
+}
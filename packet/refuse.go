@@ -0,0 +1,117 @@
+package packet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RefusePacket is sent by the listener when the connection is refused
+type RefusePacket struct {
+	TNSHeader           // ofs: 0 Len 8
+	UserReason   uint8  // ofs: 8
+	SystemReason uint8  // ofs: 9
+	DataLength   uint16 // ofs: 10
+	Data         string // ofs: 12, error descriptor like (DESCRIPTION=(ERR=12514)...)
+	ErrorCode    int    // Error code extracted from the descriptor
+}
+
+func ReadRefusePacket(b []byte) RefusePacket {
+	rp := RefusePacket{
+		TNSHeader:    ReadTNSHeader(b),
+		UserReason:   getUint8(b, 8),
+		SystemReason: getUint8(b, 9),
+		DataLength:   getUint16(b, 10),
+	}
+	rp.Data = getString(b, 12, int(rp.DataLength))
+	rp.ErrorCode, _ = strconv.Atoi(DescriptorValue(rp.Data, "ERR"))
+	return rp
+}
+
+// Event gives the refuse reason in one line
+func (rp RefusePacket) Event() string {
+	if rp.ErrorCode != 0 {
+		if msg := ErrorMessage(rp.ErrorCode); msg != "" {
+			return fmt.Sprintf("connection refused: ORA-%05d: %s", rp.ErrorCode, msg)
+		}
+		return fmt.Sprintf("connection refused: ORA-%05d", rp.ErrorCode)
+	}
+	return fmt.Sprintf("connection refused: user reason %d, system reason %d", rp.UserReason, rp.SystemReason)
+}
+
+func (rp RefusePacket) String() string {
+	sb := strings.Builder{}
+	rp.StringBuilder(&sb)
+	return sb.String()
+}
+
+func (rp RefusePacket) StringBuilder(sb *strings.Builder) {
+	rp.TNSHeader.writeFields(sb)
+	writeEol(sb)
+	rp.writeFields(sb)
+	writeEol(sb)
+	writePayload(sb, rp.TNSHeader.Buffer)
+}
+
+func (rp RefusePacket) writeFields(sb *strings.Builder) {
+	sb.WriteString("Refuse packet fields: ")
+	sb.WriteString(fmt.Sprintf("UserReason(%02x),", rp.UserReason))
+	sb.WriteString(fmt.Sprintf("SystemReason(%02x)", rp.SystemReason))
+	writeEol(sb)
+	sb.WriteString("Refuse data: ")
+	sb.WriteString(rp.Data)
+	writeEol(sb)
+	sb.WriteString(rp.Event())
+}
+
+// RedirectPacket is sent by the listener to redirect the client to another address
+type RedirectPacket struct {
+	TNSHeader         // ofs: 0 Len 8
+	DataLength uint16 // ofs: 8
+	Data       string // ofs: 10, the address optionally followed by a NUL and the connect data
+	Address    string // (ADDRESS=...) part of the data
+	Protocol   string // Target protocol
+	Host       string // Target host
+	Port       int    // Target port
+}
+
+func ReadRedirectPacket(b []byte) RedirectPacket {
+	rp := RedirectPacket{
+		TNSHeader:  ReadTNSHeader(b),
+		DataLength: getUint16(b, 8),
+	}
+	rp.Data = getString(b, 10, int(rp.DataLength))
+	rp.Address = DescriptorSection(rp.Data, "ADDRESS")
+	rp.Protocol = DescriptorValue(rp.Address, "PROTOCOL")
+	rp.Host = DescriptorValue(rp.Address, "HOST")
+	rp.Port, _ = strconv.Atoi(DescriptorValue(rp.Address, "PORT"))
+	return rp
+}
+
+// Event gives the redirection target in one line
+func (rp RedirectPacket) Event() string {
+	return "connection redirected to " + rp.Address
+}
+
+func (rp RedirectPacket) String() string {
+	sb := strings.Builder{}
+	rp.StringBuilder(&sb)
+	return sb.String()
+}
+
+func (rp RedirectPacket) StringBuilder(sb *strings.Builder) {
+	rp.TNSHeader.writeFields(sb)
+	writeEol(sb)
+	rp.writeFields(sb)
+	writeEol(sb)
+	writePayload(sb, rp.TNSHeader.Buffer)
+}
+
+func (rp RedirectPacket) writeFields(sb *strings.Builder) {
+	sb.WriteString("Redirect packet fields: ")
+	sb.WriteString(fmt.Sprintf("Protocol(%s),", rp.Protocol))
+	sb.WriteString(fmt.Sprintf("Host(%s),", rp.Host))
+	sb.WriteString(fmt.Sprintf("Port(%d)", rp.Port))
+	writeEol(sb)
+	sb.WriteString(rp.Event())
+}
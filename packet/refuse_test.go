@@ -0,0 +1,70 @@
+package packet
+
+import (
+	"testing"
+)
+
+func TestReadRefusePacket(t *testing.T) {
+	b := []byte{
+		0x00, 0x67, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x22, 0x00, 0x00, 0x5b, 0x28, 0x44, 0x45, 0x53,
+		0x43, 0x52, 0x49, 0x50, 0x54, 0x49, 0x4f, 0x4e, 0x3d, 0x28, 0x54, 0x4d, 0x50, 0x3d, 0x29, 0x28,
+		0x56, 0x53, 0x4e, 0x4e, 0x55, 0x4d, 0x3d, 0x31, 0x38, 0x36, 0x36, 0x34, 0x37, 0x35, 0x35, 0x32,
+		0x29, 0x28, 0x45, 0x52, 0x52, 0x3d, 0x31, 0x32, 0x35, 0x31, 0x34, 0x29, 0x28, 0x45, 0x52, 0x52,
+		0x4f, 0x52, 0x5f, 0x53, 0x54, 0x41, 0x43, 0x4b, 0x3d, 0x28, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x3d,
+		0x28, 0x43, 0x4f, 0x44, 0x45, 0x3d, 0x31, 0x32, 0x35, 0x31, 0x34, 0x29, 0x28, 0x45, 0x4d, 0x46,
+		0x49, 0x3d, 0x34, 0x29, 0x29, 0x29, 0x29,
+	}
+	got := ReadRefusePacket(b)
+	if got.PkType != Refuse || got.UserReason != 0x22 || got.SystemReason != 0 {
+		t.Errorf("ReadRefusePacket() = %#v", got)
+	}
+	if got.ErrorCode != 12514 {
+		t.Errorf("ErrorCode = %d, want 12514", got.ErrorCode)
+	}
+	if want := "connection refused: ORA-12514: TNS:listener does not currently know of service requested in connect descriptor"; got.Event() != want {
+		t.Errorf("Event() = %s, want %s", got.Event(), want)
+	}
+}
+
+func TestReadRedirectPacket(t *testing.T) {
+	b := []byte{
+		0x00, 0x78, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x00, 0x6e, 0x28, 0x41, 0x44, 0x44, 0x52, 0x45,
+		0x53, 0x53, 0x3d, 0x28, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x43, 0x4f, 0x4c, 0x3d, 0x54, 0x43, 0x50,
+		0x29, 0x28, 0x48, 0x4f, 0x53, 0x54, 0x3d, 0x64, 0x62, 0x6e, 0x6f, 0x64, 0x65, 0x32, 0x2e, 0x65,
+		0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x29, 0x28, 0x50, 0x4f, 0x52, 0x54,
+		0x3d, 0x31, 0x35, 0x32, 0x32, 0x29, 0x29, 0x00, 0x28, 0x44, 0x45, 0x53, 0x43, 0x52, 0x49, 0x50,
+		0x54, 0x49, 0x4f, 0x4e, 0x3d, 0x28, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x5f, 0x44, 0x41,
+		0x54, 0x41, 0x3d, 0x28, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x4e, 0x41, 0x4d, 0x45,
+		0x3d, 0x4f, 0x52, 0x43, 0x4c, 0x29, 0x29, 0x29,
+	}
+	got := ReadRedirectPacket(b)
+	if got.PkType != Redirect {
+		t.Errorf("PkType = %d, want %d", got.PkType, Redirect)
+	}
+	if want := "(ADDRESS=(PROTOCOL=TCP)(HOST=dbnode2.example.com)(PORT=1522))"; got.Address != want {
+		t.Errorf("Address = %s, want %s", got.Address, want)
+	}
+	if got.Protocol != "TCP" || got.Host != "dbnode2.example.com" || got.Port != 1522 {
+		t.Errorf("Protocol, Host, Port = %s, %s, %d", got.Protocol, got.Host, got.Port)
+	}
+}
+
+func TestDescriptorValue(t *testing.T) {
+	d := "(DESCRIPTION=(ADDRESS=(PROTOCOL=TCP)(HOST=db1)(PORT=1521))(CONNECT_DATA=(service_name=ORCL)))"
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"HOST", "db1"},
+		{"port", "1521"},
+		{"SERVICE_NAME", "ORCL"},
+		{"SID", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := DescriptorValue(d, tt.key); got != tt.want {
+				t.Errorf("DescriptorValue() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
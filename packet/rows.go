@@ -0,0 +1,130 @@
+package packet
+
+import (
+	"bytes"
+	"io"
+)
+
+// FetchCall is the client's call fetching the next rows of an open cursor
+type FetchCall struct {
+	Sequence uint8
+	CursorId uint32
+	Rows     uint32 // Number of rows asked
+}
+
+func (m FetchCall) Code() TTCCode { return TTIFUN }
+
+// Call gives the fetch function call, without its body
+func (m FetchCall) Call() FunctionCall {
+	return FunctionCall{Function: OFETCH, Sequence: m.Sequence}
+}
+
+func readFetchCall(buff *bytes.Buffer, call FunctionCall) (FetchCall, error) {
+	var err error
+	m := FetchCall{Sequence: call.Sequence}
+	m.CursorId, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Rows, err = GetUInt(buff, 4, true, true)
+	return m, err
+}
+
+// Row is a row of a result set decoded with the columns description
+type Row struct {
+	Values [][]byte // Values in columns order, nil for NULL
+}
+
+func (m Row) Code() TTCCode { return TTIRXD }
+
+// BitVector tells which columns are sent in the next row, the others are the same as in the previous row
+type BitVector struct {
+	Columns uint32 // Number of columns sent
+	Vector  []byte // One bit per column
+}
+
+func (m BitVector) Code() TTCCode { return TTIBVC }
+
+func (c TTCContext) readBitVector(buff *bytes.Buffer) (BitVector, error) {
+	m := BitVector{}
+	var err error
+	m.Columns, err = GetUInt(buff, 2, true, true)
+	if err != nil {
+		return m, err
+	}
+	l := len(c.Columns) / 8
+	if len(c.Columns)%8 > 0 {
+		l++
+	}
+	m.Vector = buff.Next(l)
+	return m, nil
+}
+
+// sent checks the bit vector for the column
+func (c TTCContext) sent(i int) bool {
+	if len(c.bitVector) == 0 {
+		return true
+	}
+	return i/8 < len(c.bitVector) && c.bitVector[i/8]&(1<<uint(i%8)) != 0
+}
+
+func (c TTCContext) readRow(buff *bytes.Buffer) (Row, error) {
+	m := Row{Values: make([][]byte, len(c.Columns))}
+	for i, col := range c.Columns {
+		if !c.sent(i) {
+			if i < len(c.previous) {
+				m.Values[i] = c.previous[i]
+			}
+			continue
+		}
+		v, err := readColumnValue(buff, col.DataType)
+		if err != nil {
+			return m, err
+		}
+		m.Values[i] = v
+	}
+	return m, nil
+}
+
+// readColumnValue reads the value of a column, nil is returned for NULL
+func readColumnValue(buff *bytes.Buffer, dataType uint8) ([]byte, error) {
+	switch dataType {
+	case typeRowId:
+		// Rba, partition id, block number and slot number when not null
+		start := buff.Bytes()
+		b, err := buff.ReadByte()
+		if err != nil || b == 0 {
+			return nil, err
+		}
+		err = SkipUInts(buff, 4, 2, 1, 4, 2)
+		if err != nil {
+			return nil, err
+		}
+		return start[:len(start)-buff.Len()], nil
+	case typeClob, typeBlob, typeBFile:
+		// LOB size, then the locator
+		l, err := GetUInt(buff, 4, true, true)
+		if err != nil || l == 0 {
+			return nil, err
+		}
+		_, err = GetInt64(buff, 8, true, true)
+		if err != nil {
+			return nil, err
+		}
+		return readClr(buff)
+	}
+	return readClr(buff)
+}
+
+// readClr reads a byte array prefixed by its length, nil is returned for NULL
+func readClr(buff *bytes.Buffer) ([]byte, error) {
+	b := buff.Bytes()
+	if len(b) == 0 {
+		return nil, io.EOF
+	}
+	if b[0] == 0 || b[0] == 0xFF {
+		buff.Next(1)
+		return nil, nil
+	}
+	return ReadBytes(buff)
+}
@@ -0,0 +1,51 @@
+package packet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadRows(t *testing.T) {
+	b := []byte{
+		0x00, 0xdf, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x01, 0x3b, 0x01, 0x03,
+		0x01, 0x02, 0x00, 0x0a, 0x00, 0x01, 0x16, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+		0x02, 0x01, 0x02, 0x02, 0x49, 0x44, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x1e,
+		0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0x69, 0x01, 0x01, 0x1e, 0x00, 0x01, 0x04, 0x01, 0x04, 0x04,
+		0x4e, 0x41, 0x4d, 0x45, 0x00, 0x00, 0x00, 0x00, 0x0c, 0x00, 0x00, 0x00, 0x01, 0x07, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x01, 0x07, 0x01, 0x07, 0x07, 0x43, 0x52, 0x45, 0x41, 0x54,
+		0x45, 0x44, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06, 0x01, 0x01, 0x03,
+		0x00, 0x01, 0x01, 0x00, 0x00, 0x00, 0x07, 0x02, 0xc1, 0x2b, 0x05, 0x53, 0x43, 0x4f, 0x54, 0x54,
+		0x07, 0x78, 0x78, 0x0a, 0x16, 0x0d, 0x2d, 0x0f, 0x15, 0x01, 0x02, 0x05, 0x07, 0x02, 0xc1, 0x08,
+		0x07, 0x78, 0x78, 0x0a, 0x16, 0x0d, 0x2d, 0x0f, 0x07, 0x02, 0xc1, 0x09, 0x04, 0x4b, 0x49, 0x4e,
+		0x47, 0x00, 0x04, 0x01, 0x03, 0x02, 0x05, 0x7b, 0x00, 0x00, 0x01, 0x02, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x19, 0x4f, 0x52, 0x41, 0x2d, 0x30, 0x31, 0x34, 0x30, 0x33, 0x3a, 0x20, 0x6e, 0x6f, 0x20,
+		0x64, 0x61, 0x74, 0x61, 0x20, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x0a, 0x09, 0x00, 0x01, 0x05,
+	}
+	date := []byte{0x78, 0x78, 0x0a, 0x16, 0x0d, 0x2d, 0x0f}
+	want := []TTCMessage{
+		Describe{
+			MaxRowSize: 59,
+			Columns: []Column{
+				{Name: "ID", DataType: 2, Precision: 10, MaxLen: 22, CharsetForm: 1},
+				{Name: "NAME", DataType: 1, MaxLen: 30, CharsetID: 873, CharsetForm: 1, MaxCharLen: 30, Nullable: true},
+				{Name: "CREATED", DataType: 12, MaxLen: 7, CharsetForm: 1, Nullable: true},
+			},
+		},
+		RowHeader{Flags: 1, Requests: 3, Iterations: 1},
+		Row{Values: [][]byte{{0xc1, 0x2b}, []byte("SCOTT"), date}},
+		BitVector{Columns: 2, Vector: []byte{0x05}},
+		Row{Values: [][]byte{{0xc1, 0x08}, []byte("SCOTT"), date}},
+		Row{Values: [][]byte{{0xc1, 0x09}, []byte("KING"), nil}},
+		Summary{RowCount: 3, ErrorCode: 1403, CursorId: 2, ErrorMessage: "ORA-01403: no data found\n"},
+		Status{Sequence: 5},
+	}
+	got, err := TTCContext{FromServer: true, TTCVersion: 11}.ReadMessages(b)
+	if err != nil {
+		t.Errorf("ReadMessages() error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadMessages() = \n%#v,\n want \n%#v", got, want)
+	}
+}
@@ -1,7 +1,6 @@
 package packet
 
 import (
-	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"runtime"
@@ -11,18 +10,18 @@ import (
 type PacketType uint8
 
 const (
-	Connect PacketType = iota + 1
-	Accept
-	Ack
-	Refuse
-	Redirect
-	Data
-	NULL
-	Abort
-	Resend
-	Marker
-	Attention
-	Control
+	Connect   PacketType = 1
+	Accept    PacketType = 2
+	Ack       PacketType = 3
+	Refuse    PacketType = 4
+	Redirect  PacketType = 5
+	Data      PacketType = 6
+	NULL      PacketType = 7
+	Abort     PacketType = 9
+	Resend    PacketType = 11
+	Marker    PacketType = 12
+	Attention PacketType = 13
+	Control   PacketType = 14
 )
 
 var packetTypeStr = map[PacketType]string{
@@ -48,31 +47,50 @@ type Stringer interface {
 	String() string
 }
 
-func PacketStringer(b []byte) Stringer {
-	t := b[4]
-	switch t {
-	case 6:
-		return ReadTNSData(b)
+// DecodedPacket is implemented by all decoded packets
+type DecodedPacket interface {
+	Stringer
+	StringBuilder
+}
+
+// ReadPacket decodes the packet according its type.
+// The version is the protocol version negotiated for the session, 0 before the Accept packet.
+// Fields beyond the end of a truncated packet are left to 0.
+func ReadPacket(b []byte, version uint16) DecodedPacket {
+	switch PacketType(getUint8(b, 4)) {
+	case Connect:
+		return ReadConnectPacket(b)
+	case Accept:
+		return ReadAcceptPacket(b)
+	case Refuse:
+		return ReadRefusePacket(b)
+	case Redirect:
+		return ReadRedirectPacket(b)
+	case Marker, Attention:
+		return ReadMarkerPacket(b, version)
+	case Data:
+		return ReadTNSDataVersion(b, version)
 	default:
-		return ReadTNSHeader(b)
+		return ReadTNSHeaderVersion(b, version)
 	}
+}
 
+func PacketStringer(b []byte) Stringer {
+	return ReadPacket(b, 0)
 }
 
 func PacketStringBuilder(b []byte) StringBuilder {
-	t := b[4]
-	switch t {
-	case 6:
-		return ReadTNSData(b)
-	default:
-		return ReadTNSHeader(b)
-	}
+	return ReadPacket(b, 0)
 }
 
+// LargeSDUVersion is the first protocol version where packets length is on 4 bytes, once the session is accepted
+const LargeSDUVersion = 315
+
 type TNSHeader struct {
 	Buffer         []byte
-	Length         uint16     // ofs: 0
-	CheckSum       uint16     // ofs: 2
+	Version        uint16     // Negotiated protocol version used to decode the header
+	Length         uint32     // ofs: 0, len 2, or len 4 from version 315
+	CheckSum       uint16     // ofs: 2, absent from version 315
 	PkType         PacketType // ofs: 4
 	Flags          uint8      // ofs: 5
 	HeaderCheckSum uint16     // ofs: 6, len 8
@@ -90,7 +108,9 @@ func (th TNSHeader) String() string {
 func (th TNSHeader) writeFields(sb *strings.Builder) {
 	sb.WriteString("Packet header: ")
 	sb.WriteString(fmt.Sprintf("PktLen(%d),", th.Length))
-	sb.WriteString(fmt.Sprintf("Chksum(%04x),", th.CheckSum))
+	if th.Version < LargeSDUVersion {
+		sb.WriteString(fmt.Sprintf("Chksum(%04x),", th.CheckSum))
+	}
 	sb.WriteString(fmt.Sprintf("PkType(%d=%s),", th.PkType, packetTypeStr[th.PkType]))
 	sb.WriteString(fmt.Sprintf("Flags(%04x),", th.Flags))
 	sb.WriteString(fmt.Sprintf("HdrChkSum(%04x)", th.HeaderCheckSum))
@@ -98,18 +118,32 @@ func (th TNSHeader) writeFields(sb *strings.Builder) {
 
 func (th TNSHeader) StringBuilder(sb *strings.Builder) {
 	th.writeFields(sb)
-	writePayload(sb, th.Buffer[8:])
+	if len(th.Buffer) > 8 {
+		writePayload(sb, th.Buffer[8:])
+	}
 }
 
+// ReadTNSHeader decodes the header of packets exchanged before the protocol negotiation
 func ReadTNSHeader(b []byte) TNSHeader {
-	return TNSHeader{
+	return ReadTNSHeaderVersion(b, 0)
+}
+
+// ReadTNSHeaderVersion decodes the header according the negotiated protocol version
+func ReadTNSHeaderVersion(b []byte, version uint16) TNSHeader {
+	th := TNSHeader{
 		Buffer:         b,
-		Length:         binary.BigEndian.Uint16(b[0:]),
-		CheckSum:       binary.BigEndian.Uint16(b[2:]),
-		PkType:         PacketType(uint8(b[4])),
-		Flags:          uint8(b[5]),
-		HeaderCheckSum: binary.BigEndian.Uint16(b[6:]),
+		Version:        version,
+		Length:         uint32(getUint16(b, 0)),
+		CheckSum:       getUint16(b, 2),
+		PkType:         PacketType(getUint8(b, 4)),
+		Flags:          getUint8(b, 5),
+		HeaderCheckSum: getUint16(b, 6),
 	}
+	if version >= LargeSDUVersion {
+		th.Length = getUint32(b, 0)
+		th.CheckSum = 0
+	}
+	return th
 }
 
 type TNSData struct {
@@ -121,12 +155,16 @@ type TNSData struct {
 }
 
 func ReadTNSData(b []byte) TNSData {
+	return ReadTNSDataVersion(b, 0)
+}
+
+func ReadTNSDataVersion(b []byte, version uint16) TNSData {
 	return TNSData{
-		TNSHeader:        ReadTNSHeader(b),
-		DataFlag:         binary.BigEndian.Uint16(b[8:]),
-		Function:         uint8(b[10]),
-		Sequence:         uint8(b[11]),
-		ExtendedFunction: uint8(b[12]),
+		TNSHeader:        ReadTNSHeaderVersion(b, version),
+		DataFlag:         getUint16(b, 8),
+		Function:         getUint8(b, 10),
+		Sequence:         getUint8(b, 11),
+		ExtendedFunction: getUint8(b, 12),
 	}
 }
 func (td TNSData) String() string {
@@ -0,0 +1,94 @@
+package packet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadTNSHeaderVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       []byte
+		version uint16
+		want    TNSHeader
+	}{
+		{
+			name:    "2 bytes length before negotiation",
+			b:       []byte{0x00, 0x25, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00},
+			version: 0,
+			want:    TNSHeader{Length: 0x25, PkType: Data},
+		},
+		{
+			name:    "2 bytes length with version 314",
+			b:       []byte{0x00, 0x25, 0x00, 0x00, 0x06, 0x20, 0x00, 0x00, 0x00, 0x00},
+			version: 314,
+			want:    TNSHeader{Version: 314, Length: 0x25, PkType: Data, Flags: 0x20},
+		},
+		{
+			name:    "4 bytes length with version 318",
+			b:       []byte{0x00, 0x01, 0x86, 0xa0, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00},
+			version: 318,
+			want:    TNSHeader{Version: 318, Length: 100000, PkType: Data},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReadTNSHeaderVersion(tt.b, tt.version)
+			got.Buffer = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadTNSHeaderVersion() = \n%#v,\n want \n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadPacketLargeSDU(t *testing.T) {
+	b := []byte{
+		0x00, 0x00, 0x00, 0x0d, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x5e, 0x01,
+	}
+	sb := strings.Builder{}
+	ReadPacket(b, 318).StringBuilder(&sb)
+	got := sb.String()
+	if !strings.HasPrefix(got, "Packet header: PktLen(13),PkType(6=Data),") {
+		t.Errorf("ReadPacket() = %q, want the 4 bytes length without checksum", got)
+	}
+}
+
+func TestReadPacket_truncated(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+		want string
+	}{
+		{
+			name: "data packet cut in the data flag",
+			b:    []byte{0x00, 0x0d, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00},
+			want: "Data packet fields: DataFlag(0000000000000000),Function(00),Sequence(0),ExtendedFunction(00),",
+		},
+		{
+			name: "data packet cut before the function",
+			b:    []byte{0x00, 0x0d, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00},
+			want: "Function(00),Sequence(0),ExtendedFunction(00),",
+		},
+		{
+			name: "data packet cut in the TTC header",
+			b:    []byte{0x00, 0x0d, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x5e},
+			want: "Function(03),Sequence(94),ExtendedFunction(00),",
+		},
+		{
+			name: "header cut after the packet type",
+			b:    []byte{0x00, 0x0d, 0x00, 0x00, 0x0e},
+			want: "PkType(14=Control),",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := strings.Builder{}
+			ReadPacket(tt.b, 0).StringBuilder(&sb)
+			if got := sb.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("ReadPacket() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,767 @@
+package packet
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// TTCCode identifies a TTC message in a data packet
+type TTCCode uint8
+
+const (
+	TTIPRO  TTCCode = 0x01 // Protocol negotiation
+	TTIDTY  TTCCode = 0x02 // Data types negotiation
+	TTIFUN  TTCCode = 0x03 // Function call
+	TTIOER  TTCCode = 0x04 // End of call summary, with error code when any
+	TTIRXH  TTCCode = 0x06 // Row header
+	TTIRXD  TTCCode = 0x07 // Row data
+	TTIRPA  TTCCode = 0x08 // Return parameters
+	TTISTA  TTCCode = 0x09 // Status, end of the response
+	TTIIOV  TTCCode = 0x0B // IO vector, direction of binds
+	TTILOBD TTCCode = 0x0E // LOB data
+	TTIDCB  TTCCode = 0x10 // Describe information
+	TTIPFN  TTCCode = 0x11 // Piggyback function, sent before the main call
+	TTIBVC  TTCCode = 0x15 // Bit vector, columns sent in the next row
+)
+
+var ttcCodeStr = map[TTCCode]string{
+	TTIPRO:  "Protocol negotiation",
+	TTIDTY:  "Data types",
+	TTIFUN:  "Function call",
+	TTIOER:  "Summary",
+	TTIRXH:  "Row header",
+	TTIRXD:  "Row data",
+	TTIRPA:  "Return parameters",
+	TTISTA:  "Status",
+	TTIIOV:  "IO vector",
+	TTILOBD: "LOB data",
+	TTIDCB:  "Describe",
+	TTIPFN:  "Piggyback",
+	TTIBVC:  "Bit vector",
+}
+
+func (c TTCCode) String() string {
+	if s, ok := ttcCodeStr[c]; ok {
+		return s
+	}
+	return fmt.Sprintf("TTC(%02x)", uint8(c))
+}
+
+// FunctionCode identifies the function of a call or a piggyback
+type FunctionCode uint8
+
+const (
+	OEXEC     FunctionCode = 0x04 // Execute
+	OFETCH    FunctionCode = 0x05 // Fetch
+	OCOMMIT   FunctionCode = 0x0E // Commit
+	OROLLBACK FunctionCode = 0x0F // Rollback
+	OPARSE    FunctionCode = 0x22 // Parse, V7 layout
+	OVERSION  FunctionCode = 0x3B // Get server version
+	OALL7     FunctionCode = 0x47 // Parse, bind, execute and fetch, V7 layout
+	OSQL7     FunctionCode = 0x4A // Parse and execute, V7 layout
+	OALL8     FunctionCode = 0x5E // Parse, bind, execute and fetch
+	OLOBOPS   FunctionCode = 0x60 // LOB operations
+	OENDTOEND FunctionCode = 0x87 // Set end-to-end attributes: module, action, client identifier
+	OTXSE     FunctionCode = 0x67 // Start or end a transaction, XA
+	OTXEN     FunctionCode = 0x68 // Transaction control, XA
+	OCCA      FunctionCode = 0x69 // Close cursors
+	OAUTH     FunctionCode = 0x73 // Authentication
+	OSESSKEY  FunctionCode = 0x76 // Session key exchange
+	OCANA     FunctionCode = 0x78 // Cancel all
+	OAQEQ     FunctionCode = 0x79 // AQ enqueue
+	OAQDQ     FunctionCode = 0x7A // AQ dequeue
+	OKEYVAL   FunctionCode = 0x9A // Session state key / values
+)
+
+var functionCodeStr = map[FunctionCode]string{
+	OEXEC:     "OEXEC",
+	OFETCH:    "OFETCH",
+	OCOMMIT:   "OCOMMIT",
+	OROLLBACK: "OROLLBACK",
+	OPARSE:    "OPARSE",
+	OVERSION:  "OVERSION",
+	OALL7:     "OALL7",
+	OSQL7:     "OSQL7",
+	OALL8:     "OALL8",
+	OLOBOPS:   "OLOBOPS",
+	OENDTOEND: "OENDTOEND",
+	OTXSE:     "OTXSE",
+	OTXEN:     "OTXEN",
+	OCCA:      "OCCA",
+	OAUTH:     "OAUTH",
+	OSESSKEY:  "OSESSKEY",
+	OCANA:     "OCANA",
+	OAQEQ:     "OAQEQ",
+	OAQDQ:     "OAQDQ",
+	OKEYVAL:   "OKEYVAL",
+}
+
+func (f FunctionCode) String() string {
+	if s, ok := functionCodeStr[f]; ok {
+		return s
+	}
+	return fmt.Sprintf("Function(%02x)", uint8(f))
+}
+
+// TTCMessage is implemented by all decoded TTC messages
+type TTCMessage interface {
+	Code() TTCCode
+}
+
+// TTCContext gives what the decoding of some messages depends on
+type TTCContext struct {
+	FromServer      bool         // The packet is sent by the server
+	Function        FunctionCode // Function call the server's response is for
+	CompileTimeCaps []byte       // Client's compile time capabilities, sent in the data types negotiation
+	RuntimeCaps     []byte       // Client's runtime capabilities, sent in the data types negotiation
+	TTCVersion      uint8        // TTC version of the session, from compile time capabilities
+	Columns         []Column     // Columns of the cursor, needed for decoding rows
+	LobCall         LobCall      // Call of an OLOBOPS response, its parameters follow the call
+	RowCounts       bool         // The call asked for the rows processed by each iteration of array DML
+	Binds           []Column     // Binds of the call, values of output binds are decoded like columns
+	Returning       bool         // Row data holds the values returned by a RETURNING INTO clause, Columns are its binds
+
+	bitVector []byte   // Columns sent in the next row
+	previous  [][]byte // Values of the previous row
+}
+
+// ReadTTCMessages walks the TTC messages of a data packet without context
+func ReadTTCMessages(b []byte) ([]TTCMessage, error) {
+	return TTCContext{}.ReadMessages(b)
+}
+
+// ReadMessages walks the TTC messages of a data packet and decodes them in order.
+// A message that can't be delimited ends the walk, it is returned as an UnknownMessage holding the remaining of the packet.
+// On error, messages decoded so far are returned.
+func (c TTCContext) ReadMessages(b []byte) ([]TTCMessage, error) {
+	if len(b) <= 10 || PacketType(b[4]) != Data {
+		return nil, nil
+	}
+	buff := bytes.NewBuffer(b[10:])
+	msgs := []TTCMessage{}
+	for buff.Len() > 0 {
+		m, err := c.readMessage(buff)
+		if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, m)
+
+		// Rows decoding depends on previous messages
+		switch m := m.(type) {
+		case Describe:
+			c.Columns = m.Columns
+		case RowHeader:
+			c.bitVector = m.BitVector
+		case BitVector:
+			c.bitVector = m.Vector
+		case Row:
+			c.previous = m.Values
+			c.bitVector = nil
+		case IOVector:
+			// The next row data holds the values of output binds
+			c.Columns = c.outBinds(m.Directions)
+			c.bitVector, c.previous = nil, nil
+		}
+	}
+	return msgs, nil
+}
+
+// readMessage dispatches the message to its decoder
+func (c TTCContext) readMessage(buff *bytes.Buffer) (TTCMessage, error) {
+	b, err := buff.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	code := TTCCode(b)
+	switch code {
+	case TTIPRO:
+		return readProtocolNegotiation(buff, c.FromServer)
+	case TTIDTY:
+		if c.FromServer {
+			return c.readServerDataTypes(buff)
+		}
+		return readClientDataTypes(buff)
+	case TTIFUN:
+		return c.readFunctionCall(buff)
+	case TTIPFN:
+		return readPiggyback(buff)
+	case TTIOER:
+		return readSummary(buff)
+	case TTIRXH:
+		return readRowHeader(buff)
+	case TTIRXD:
+		if c.Returning && len(c.Columns) > 0 {
+			return c.readReturnedValues(buff)
+		}
+		if len(c.Columns) > 0 {
+			return c.readRow(buff)
+		}
+		// Row length can't be determined without columns description.
+		// Keep the remaining of the packet as is.
+		return RowData{Data: buff.Next(buff.Len())}, nil
+	case TTIDCB:
+		return c.readDescribe(buff)
+	case TTIIOV:
+		return readIOVector(buff)
+	case TTIBVC:
+		return c.readBitVector(buff)
+	case TTIRPA:
+		switch c.Function {
+		case OVERSION:
+			return readServerVersion(buff)
+		case OSESSKEY, OAUTH:
+			return readAuthParameters(buff)
+		case OLOBOPS:
+			return c.readLobParameters(buff)
+		case OAQEQ:
+			return readAQEnqueueParameters(buff)
+		case OAQDQ:
+			return c.readAQDequeueParameters(buff)
+		case OTXSE:
+			return readTxnSwitchParameters(buff)
+		case OTXEN:
+			return readTxnChangeStateParameters(buff)
+		case OALL7, OSQL7, OPARSE, OEXEC:
+			return readV7ReturnParameters(buff)
+		}
+		return c.readReturnParameters(buff)
+	case TTISTA:
+		return readStatus(buff)
+	case TTILOBD:
+		return readLobData(buff)
+	default:
+		return UnknownMessage{MessageCode: code, Data: buff.Next(buff.Len())}, nil
+	}
+}
+
+// UnknownMessage is a message not decoded yet, it holds the remaining of the packet
+type UnknownMessage struct {
+	MessageCode TTCCode
+	Data        []byte
+}
+
+func (m UnknownMessage) Code() TTCCode { return m.MessageCode }
+
+// FunctionCall is the main call of a client's request.
+// The body layout depends on the function, it's the remaining of the packet.
+type FunctionCall struct {
+	Function FunctionCode
+	Sequence uint8
+	Body     []byte
+}
+
+func (m FunctionCall) Code() TTCCode { return TTIFUN }
+
+// Call gives the function call itself
+func (m FunctionCall) Call() FunctionCall { return m }
+
+// Caller is implemented by function calls, whether their body is decoded or not
+type Caller interface {
+	TTCMessage
+	Call() FunctionCall
+}
+
+func (c TTCContext) readFunctionCall(buff *bytes.Buffer) (TTCMessage, error) {
+	m := FunctionCall{}
+	b, err := buff.ReadByte()
+	if err != nil {
+		return m, err
+	}
+	m.Function = FunctionCode(b)
+	m.Sequence, err = buff.ReadByte()
+	if err != nil {
+		return m, err
+	}
+	switch m.Function {
+	case OSESSKEY, OAUTH:
+		return readAuthCall(buff, m)
+	case OFETCH:
+		return readFetchCall(buff, m)
+	case OLOBOPS:
+		if c.TTCVersion > 0 {
+			// The layout depends on the TTC version
+			return c.readLobCall(buff, m)
+		}
+	case OAQEQ:
+		if c.TTCVersion > 0 {
+			return c.readAQEnqueue(buff, m)
+		}
+	case OAQDQ:
+		if c.TTCVersion > 0 {
+			return c.readAQDequeue(buff, m)
+		}
+	case OTXSE:
+		return readTxnSwitch(buff, m)
+	case OTXEN:
+		return readTxnChangeState(buff, m)
+	}
+	m.Body = buff.Next(buff.Len())
+	return m, nil
+}
+
+// Piggyback is a function sent in front of the main call, without its own round trip.
+// It's used for functions not decoded yet.
+type Piggyback struct {
+	Function FunctionCode
+	Sequence uint8
+	Body     []byte // Piggyback's own bytes
+}
+
+func (m Piggyback) Code() TTCCode { return TTIPFN }
+
+// CursorClose is the OCCA piggyback, the client closes cursors it doesn't use anymore
+type CursorClose struct {
+	Sequence uint8
+	Ids      []uint32 // Closed cursors
+}
+
+func (m CursorClose) Code() TTCCode { return TTIPFN }
+
+// CancelAll is the OCANA piggyback, the client cancels all the operations in progress
+type CancelAll struct {
+	Sequence uint8
+}
+
+func (m CancelAll) Code() TTCCode { return TTIPFN }
+
+// SessionState is the OKEYVAL piggyback, the client updates session state key / values.
+// The layout isn't decoded, the body holds the piggyback's bytes.
+type SessionState struct {
+	Sequence uint8
+	Body     []byte
+}
+
+func (m SessionState) Code() TTCCode { return TTIPFN }
+
+// End-to-end attributes set by the piggyback
+const (
+	EndToEndClientId   uint32 = 0x0001
+	EndToEndModule     uint32 = 0x0008
+	EndToEndAction     uint32 = 0x0010
+	EndToEndClientInfo uint32 = 0x0100
+	EndToEndDbOp       uint32 = 0x0200
+)
+
+// EndToEnd is the OENDTOEND piggyback, the client sets end-to-end attributes like DBMS_APPLICATION_INFO's module and action.
+// Attributes not in Flags are unchanged, the ones in Flags without value are cleared.
+type EndToEnd struct {
+	Sequence   uint8
+	Flags      uint32
+	ClientId   string // Client identifier
+	Module     string
+	Action     string
+	ClientInfo string
+	DbOp       string // Database operation
+}
+
+func (m EndToEnd) Code() TTCCode { return TTIPFN }
+
+func readEndToEnd(buff *bytes.Buffer, seq uint8) (EndToEnd, error) {
+	var err error
+	m := EndToEnd{Sequence: seq}
+	err = SkipUInts(buff, 1, 1) // Session name and serial pointers
+	if err != nil {
+		return m, err
+	}
+	m.Flags, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+
+	// Pointer and length of: client identifier, module, action, execution context id,
+	// then context sequence, client info, kernel stack, kernel target, database operation
+	var lengths [8]uint32
+	for i := range lengths {
+		if i == 4 {
+			err = SkipUInts(buff, 1, 4) // Execution context sequence
+			if err != nil {
+				return m, err
+			}
+		}
+		err = SkipUInts(buff, 1)
+		if err != nil {
+			return m, err
+		}
+		lengths[i], err = GetUInt(buff, 4, true, true)
+		if err != nil {
+			return m, err
+		}
+	}
+
+	values := [8]*string{&m.ClientId, &m.Module, &m.Action, nil, &m.ClientInfo, nil, nil, &m.DbOp}
+	for i, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		var b []byte
+		b, err = ReadBytes(buff)
+		if err != nil {
+			return m, err
+		}
+		if values[i] != nil {
+			*values[i] = string(b)
+		}
+	}
+	return m, nil
+}
+
+func readPiggyback(buff *bytes.Buffer) (TTCMessage, error) {
+	m := Piggyback{}
+	b, err := buff.ReadByte()
+	if err != nil {
+		return m, err
+	}
+	m.Function = FunctionCode(b)
+	m.Sequence, err = buff.ReadByte()
+	if err != nil {
+		return m, err
+	}
+	if m.Function == OENDTOEND {
+		return readEndToEnd(buff, m.Sequence)
+	}
+	if m.Function == OCCA {
+		// Pointer, then the array of cursor ids
+		c := CursorClose{Sequence: m.Sequence}
+		err = SkipUInts(buff, 1)
+		if err != nil {
+			return c, err
+		}
+		c.Ids, err = readUIntList(buff, 4)
+		return c, err
+	}
+
+	// The layout isn't known, the piggyback ends where the next call begins
+	start := buff.Bytes()
+	l := nextCall(start, m.Sequence+1)
+	if l < 0 {
+		return UnknownMessage{MessageCode: TTIPFN, Data: append([]byte{b, m.Sequence}, buff.Next(buff.Len())...)}, nil
+	}
+	m.Body = buff.Next(l)
+	switch m.Function {
+	case OCANA:
+		return CancelAll{Sequence: m.Sequence}, nil
+	case OKEYVAL:
+		return SessionState{Sequence: m.Sequence, Body: m.Body}, nil
+	}
+	return m, nil
+}
+
+// nextCall gives the position of the next function call or piggyback, -1 when not found.
+// Calls sent together have consecutive sequence numbers, the function must be a known one.
+func nextCall(b []byte, seq uint8) int {
+	for i := 0; i+2 < len(b); i++ {
+		if (TTCCode(b[i]) != TTIFUN && TTCCode(b[i]) != TTIPFN) || b[i+2] != seq {
+			continue
+		}
+		if _, ok := functionCodeStr[FunctionCode(b[i+1])]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// Summary is the end of call summary sent by the server
+type Summary struct {
+	RowCount     uint32 // Rows processed or fetched by the call
+	ErrorCode    uint32 // ORA error code, 0 when the call succeeded
+	CursorId     uint32 // Cursor used by the server for the call
+	ErrorPos     uint32 // Position of the error in the statement
+	ErrorMessage string // Error message as sent by the server
+}
+
+func (m Summary) Code() TTCCode { return TTIOER }
+
+func readSummary(buff *bytes.Buffer) (Summary, error) {
+	var err error
+	m := Summary{}
+
+	m.RowCount, err = GetUInt(buff, 4, true, true) // Current row number
+	if err != nil {
+		return m, err
+	}
+	m.ErrorCode, err = GetUInt(buff, 2, true, true) // Return code
+	if err != nil {
+		return m, err
+	}
+	err = SkipUInts(buff, 2, 2) // Array element with error, array element error number
+	if err != nil {
+		return m, err
+	}
+	m.CursorId, err = GetUInt(buff, 2, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.ErrorPos, err = GetUInt(buff, 2, true, true)
+	if err != nil {
+		return m, err
+	}
+	// Sql type, fatal flag, flags, user cursor options, UPI parameter, warning flag
+	err = SkipUInts(buff, 1, 1, 2, 2, 1, 1)
+	if err != nil {
+		return m, err
+	}
+	// Rba, partition id, table id, block number, slot number, OS error,
+	// statement number, call number, padding, successful iterations
+	err = SkipUInts(buff, 4, 2, 1, 4, 2, 4, 1, 1, 2, 4)
+	if err != nil {
+		return m, err
+	}
+
+	// Logical rowid
+	_, err = readDlc(buff)
+	if err != nil {
+		return m, err
+	}
+
+	// Batch errors: codes then row offsets
+	for _, size := range []int{2, 4} {
+		_, err = readUIntArray(buff, size)
+		if err != nil {
+			return m, err
+		}
+	}
+
+	// Batch errors messages
+	var n uint32
+	n, err = GetUInt(buff, 2, true, true)
+	if err != nil {
+		return m, err
+	}
+	if n > 0 {
+		_, err = buff.ReadByte() // Array flag
+		if err != nil {
+			return m, err
+		}
+		for i := 0; i < int(n); i++ {
+			_, err = GetUInt(buff, 2, true, true) // Message length
+			if err != nil {
+				return m, err
+			}
+			_, err = ReadBytes(buff)
+			if err != nil {
+				return m, err
+			}
+		}
+	}
+
+	if m.ErrorCode != 0 {
+		var msg []byte
+		msg, err = ReadBytes(buff)
+		if err != nil {
+			return m, err
+		}
+		m.ErrorMessage = string(msg)
+	}
+	return m, nil
+}
+
+// RowHeader precedes row data
+type RowHeader struct {
+	Flags        uint8
+	Requests     uint32 // Number of requests
+	Iteration    uint32 // Iteration number
+	Iterations   uint32 // Number of iterations
+	BufferLength uint32
+	BitVector    []byte // Columns sent in the row, when some are the same as the previous row
+	RowId        []byte
+}
+
+func (m RowHeader) Code() TTCCode { return TTIRXH }
+
+func readRowHeader(buff *bytes.Buffer) (RowHeader, error) {
+	var err error
+	m := RowHeader{}
+	m.Flags, err = buff.ReadByte()
+	if err != nil {
+		return m, err
+	}
+	m.Requests, err = GetUInt(buff, 2, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Iteration, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Iterations, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.BufferLength, err = GetUInt(buff, 2, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.BitVector, err = readDlc(buff)
+	if err != nil {
+		return m, err
+	}
+	m.RowId, err = readDlc(buff)
+	return m, err
+}
+
+// Bind direction bits of the IO vector
+const (
+	BindOut uint8 = 0x10
+	BindIn  uint8 = 0x20
+)
+
+// IOVector gives the direction of each bind, it precedes the values of output binds.
+// It has the layout of a row header whose bit vector holds a byte per bind.
+type IOVector struct {
+	Directions []byte // BindIn and BindOut bits, in binds order
+}
+
+func (m IOVector) Code() TTCCode { return TTIIOV }
+
+func readIOVector(buff *bytes.Buffer) (IOVector, error) {
+	h, err := readRowHeader(buff)
+	return IOVector{Directions: h.BitVector}, err
+}
+
+// outBinds gives the binds sent back by the server
+func (c TTCContext) outBinds(directions []byte) []Column {
+	cols := []Column{}
+	for i, d := range directions {
+		if d&BindOut != 0 && i < len(c.Binds) {
+			cols = append(cols, c.Binds[i])
+		}
+	}
+	return cols
+}
+
+// ReturnedValues holds the values returned into the binds of a RETURNING INTO clause
+type ReturnedValues struct {
+	Values [][][]byte // Per bind, a value per processed row, nil for NULL
+}
+
+func (m ReturnedValues) Code() TTCCode { return TTIRXD }
+
+func (c TTCContext) readReturnedValues(buff *bytes.Buffer) (ReturnedValues, error) {
+	m := ReturnedValues{}
+	for _, col := range c.Columns {
+		n, err := GetUInt(buff, 4, true, true)
+		if err != nil {
+			return m, err
+		}
+		values := [][]byte{}
+		for i := 0; i < int(n); i++ {
+			var v []byte
+			v, err = readColumnValue(buff, col.DataType)
+			if err != nil {
+				return m, err
+			}
+			values = append(values, v)
+		}
+		m.Values = append(m.Values, values)
+	}
+	return m, nil
+}
+
+// RowData holds the remaining of the packet, rows can't be delimited without the columns description
+type RowData struct {
+	Data []byte
+}
+
+func (m RowData) Code() TTCCode { return TTIRXD }
+
+// ReturnParameters is sent by the server after an execution call
+type ReturnParameters struct {
+	Values        []uint32 // AL8O4 values
+	TransactionId []byte
+	KeyValues     []KeyValue
+	RowCounts     []uint64 // Rows processed by each iteration of array DML, when asked by the call
+}
+
+// KeyValue is a key / value pair with its flag
+type KeyValue struct {
+	Key   []byte
+	Value []byte
+	Flag  uint32
+}
+
+func (m ReturnParameters) Code() TTCCode { return TTIRPA }
+
+func (c TTCContext) readReturnParameters(buff *bytes.Buffer) (ReturnParameters, error) {
+	m := ReturnParameters{}
+	var err error
+
+	m.Values, err = readUIntList(buff, 2)
+	if err != nil {
+		return m, err
+	}
+
+	// Transaction id
+	n, err := GetUInt(buff, 2, true, true)
+	if err != nil {
+		return m, err
+	}
+	err = checkLength(buff, int(n))
+	if err != nil {
+		return m, err
+	}
+	m.TransactionId = buff.Next(int(n))
+
+	// Key / values
+	n, err = GetUInt(buff, 2, true, true)
+	if err != nil {
+		return m, err
+	}
+	for i := 0; i < int(n); i++ {
+		kv := KeyValue{}
+		kv.Key, kv.Value, kv.Flag, err = readKeyVal(buff)
+		if err != nil {
+			return m, err
+		}
+		m.KeyValues = append(m.KeyValues, kv)
+	}
+
+	if c.RowCounts {
+		n, err = GetUInt(buff, 4, true, true)
+		if err != nil {
+			return m, err
+		}
+		for i := 0; i < int(n); i++ {
+			var rc int64
+			rc, err = GetInt64(buff, 8, true, true)
+			if err != nil {
+				return m, err
+			}
+			m.RowCounts = append(m.RowCounts, uint64(rc))
+		}
+	}
+	return m, nil
+}
+
+// V7ReturnParameters is sent by the server after the calls of V7 clients.
+// It holds only the AL8O4 values, the transaction id and the key / values came with OALL8.
+type V7ReturnParameters struct {
+	Values []uint32 // AL8O4 values
+}
+
+func (m V7ReturnParameters) Code() TTCCode { return TTIRPA }
+
+func readV7ReturnParameters(buff *bytes.Buffer) (V7ReturnParameters, error) {
+	var err error
+	m := V7ReturnParameters{}
+	m.Values, err = readUIntList(buff, 2)
+	return m, err
+}
+
+// Status ends the server's response
+type Status struct {
+	CallStatus uint32
+	Sequence   uint32 // End to end sequence number
+}
+
+func (m Status) Code() TTCCode { return TTISTA }
+
+func readStatus(buff *bytes.Buffer) (Status, error) {
+	var err error
+	m := Status{}
+	m.CallStatus, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Sequence, err = GetUInt(buff, 2, true, true)
+	return m, err
+}
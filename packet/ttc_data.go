@@ -0,0 +1,202 @@
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// GetInt64 reads an integer, when compress is set, the size is given by the first byte
+func GetInt64(r *bytes.Buffer, size int, compress bool, bigEndian bool) (int64, error) {
+	var ret int64
+
+	negFlag := false
+	if compress {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if (b & 0x80) != 0 {
+			negFlag = true
+		}
+		size = int(b & 0x7F)
+		bigEndian = true
+	}
+	if size == 0 {
+		return 0, nil
+	}
+	if size > 8 {
+		return 0, fmt.Errorf("GetInt64 unexpected integer size %d", size)
+	}
+	buff := r.Next(size)
+	temp := make([]byte, 8)
+	if bigEndian {
+		copy(temp[8-size:], buff)
+		ret = int64(binary.BigEndian.Uint64(temp))
+	} else {
+		copy(temp[:size], buff)
+		ret = int64(binary.LittleEndian.Uint64(temp))
+	}
+	if negFlag {
+		ret = ret * -1
+	}
+	return ret, nil
+}
+
+// GetInt reads a 32 bits integer
+func GetInt(r *bytes.Buffer, size int, compress bool, bigEndian bool) (int32, error) {
+	temp, err := GetInt64(r, size, compress, bigEndian)
+	if err != nil {
+		return 0, err
+	}
+	return int32(temp), nil
+}
+
+// GetUInt reads a 32 bits unsigned integer
+func GetUInt(r *bytes.Buffer, size int, compress bool, bigEndian bool) (uint32, error) {
+	i, err := GetInt(r, size, compress, bigEndian)
+	return uint32(i), err
+}
+
+// ReadBytes reads a byte array prefixed by its length, long arrays are sent by chunks of 0x40 bytes
+func ReadBytes(buff *bytes.Buffer) ([]byte, error) {
+	out := make([]byte, 0, 40)
+	var l, b byte
+	var err error
+	hasChunks := false
+
+	l, err = buff.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if l == 0xFE {
+		// Marker for buffer bigger than 0x40
+		l, err = buff.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		hasChunks = true
+	}
+
+extern:
+	for l > 0 {
+		for l > 0 {
+			l--
+			b, err = buff.ReadByte()
+			if err != nil {
+				break extern
+			}
+			out = append(out, b)
+		}
+		if hasChunks {
+			l, err = buff.ReadByte()
+			if err != nil {
+				break extern
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SkipUInts discards several integers. Size 1 is a single byte, other sizes are compressed integers
+func SkipUInts(buff *bytes.Buffer, sizes ...int) error {
+	var err error
+	for _, size := range sizes {
+		if size == 1 {
+			_, err = buff.ReadByte()
+		} else {
+			_, err = GetUInt(buff, size, true, true)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkLength rejects a length or a count read on the wire that exceeds the remaining bytes,
+// each element taking at least one byte. A malformed message would otherwise allocate a huge buffer.
+func checkLength(buff *bytes.Buffer, n int) error {
+	if n < 0 || n > buff.Len() {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// readUIntArray reads an array of compressed integers: count, flag and values
+func readUIntArray(buff *bytes.Buffer, size int) ([]uint32, error) {
+	n, err := GetUInt(buff, size, true, true)
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	_, err = buff.ReadByte() // Array flag
+	if err != nil {
+		return nil, err
+	}
+	err = checkLength(buff, int(n))
+	if err != nil {
+		return nil, err
+	}
+	a := make([]uint32, n)
+	for i := range a {
+		a[i], err = GetUInt(buff, size, true, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// readDlc reads a byte array prefixed by its length
+func readDlc(buff *bytes.Buffer) ([]byte, error) {
+	l, err := GetUInt(buff, 4, true, true)
+	if err != nil || l == 0 {
+		return nil, err
+	}
+	b, err := ReadBytes(buff)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > int(l) {
+		b = b[:l]
+	}
+	return b, nil
+}
+
+// readKeyVal reads a key / value pair with its flag
+func readKeyVal(buff *bytes.Buffer) (key []byte, val []byte, flag uint32, err error) {
+	key, err = readDlc(buff)
+	if err != nil {
+		return
+	}
+	val, err = readDlc(buff)
+	if err != nil {
+		return
+	}
+	flag, err = GetUInt(buff, 4, true, true)
+	return
+}
+
+// readUIntList reads a list of compressed 4 bytes integers prefixed by its count, without array flag
+func readUIntList(buff *bytes.Buffer, countSize int) ([]uint32, error) {
+	n, err := GetUInt(buff, countSize, true, true)
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	err = checkLength(buff, int(n))
+	if err != nil {
+		return nil, err
+	}
+	a := make([]uint32, n)
+	for i := range a {
+		a[i], err = GetUInt(buff, 4, true, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
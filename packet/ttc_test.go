@@ -0,0 +1,173 @@
+package packet
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestReadTTCMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  TTCContext
+		b    []byte
+		want []TTCMessage
+	}{
+		{
+			name: "close cursor piggyback before OALL8",
+			b: []byte{
+				0x00, 0x1c, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x11, 0x69, 0x15, 0x01, 0x01, 0x01,
+				0x01, 0x02, 0x03, 0x5e, 0x16, 0x02, 0x80, 0x69, 0x00, 0x01, 0x01, 0xae,
+			},
+			want: []TTCMessage{
+				CursorClose{Sequence: 0x15, Ids: []uint32{2}},
+				FunctionCall{Function: OALL8, Sequence: 0x16, Body: []byte{0x02, 0x80, 0x69, 0x00, 0x01, 0x01, 0xae}},
+			},
+		},
+		{
+			name: "cancel all and session state piggybacks before OALL8",
+			b: []byte{
+				0x00, 0x1f, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x11, 0x78, 0x05, 0x11, 0x9a, 0x06,
+				0x01, 0x04, 0x02, 0x01, 0x01, 0x03, 0x5e, 0x07, 0x02, 0x80, 0x69, 0x00, 0x01, 0x01, 0xae,
+			},
+			want: []TTCMessage{
+				CancelAll{Sequence: 0x05},
+				SessionState{Sequence: 0x06, Body: []byte{0x01, 0x04, 0x02, 0x01, 0x01}},
+				FunctionCall{Function: OALL8, Sequence: 0x07, Body: []byte{0x02, 0x80, 0x69, 0x00, 0x01, 0x01, 0xae}},
+			},
+		},
+		{
+			name: "end-to-end attributes piggyback before OALL8",
+			b: []byte{
+				0x00, 0x40, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x11, 0x87, 0x15, 0x00, 0x00, 0x01,
+				0x18, 0x00, 0x00, 0x01, 0x01, 0x06, 0x01, 0x01, 0x09, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x06, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x53, 0x09, 0x4e, 0x45, 0x57,
+				0x5f, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x03, 0x5e, 0x16, 0x02, 0x80, 0x69, 0x00, 0x01, 0x01, 0xae,
+			},
+			want: []TTCMessage{
+				EndToEnd{Sequence: 0x15, Flags: EndToEndModule | EndToEndAction, Module: "ORDERS", Action: "NEW_ORDER"},
+				FunctionCall{Function: OALL8, Sequence: 0x16, Body: []byte{0x02, 0x80, 0x69, 0x00, 0x01, 0x01, 0xae}},
+			},
+		},
+		{
+			name: "OALL8 alone",
+			b: []byte{
+				0x00, 0x14, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x5e, 0x16, 0x02, 0x80, 0x69,
+				0x00, 0x01, 0x01, 0xae,
+			},
+			want: []TTCMessage{
+				FunctionCall{Function: OALL8, Sequence: 0x16, Body: []byte{0x02, 0x80, 0x69, 0x00, 0x01, 0x01, 0xae}},
+			},
+		},
+		{
+			name: "OFETCH of 10 rows on cursor 2",
+			b: []byte{
+				0x00, 0x11, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x05, 0x09, 0x01, 0x02, 0x01,
+				0x0a,
+			},
+			want: []TTCMessage{
+				FetchCall{Sequence: 0x09, CursorId: 2, Rows: 10},
+			},
+		},
+		{
+			name: "return parameters, summary and status",
+			b: []byte{
+				0x00, 0x33, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x01, 0x03, 0x00, 0x01, 0x01,
+				0x00, 0x00, 0x00, 0x04, 0x01, 0x01, 0x00, 0x00, 0x00, 0x01, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x09, 0x00, 0x00,
+			},
+			want: []TTCMessage{
+				ReturnParameters{Values: []uint32{0, 1, 0}, TransactionId: []byte{}},
+				Summary{RowCount: 1, CursorId: 2},
+				Status{},
+			},
+		},
+		{
+			name: "return parameters of OALL7, summary and status",
+			ctx:  TTCContext{FromServer: true, Function: OALL7},
+			b: []byte{
+				0x00, 0x31, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x01, 0x03, 0x00, 0x01, 0x01,
+				0x00, 0x04, 0x01, 0x01, 0x00, 0x00, 0x00, 0x01, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09, 0x00,
+				0x00,
+			},
+			want: []TTCMessage{
+				V7ReturnParameters{Values: []uint32{0, 1, 0}},
+				Summary{RowCount: 1, CursorId: 2},
+				Status{},
+			},
+		},
+		{
+			name: "IO vector, output values can't be decoded without binds",
+			b: []byte{
+				0x00, 0x1a, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0b, 0x01, 0x00, 0x00, 0x00, 0x00,
+				0x01, 0x02, 0x02, 0x10, 0x20, 0x00, 0x07, 0x02, 0xc1, 0x2b,
+			},
+			want: []TTCMessage{
+				IOVector{Directions: []byte{BindOut, BindIn}},
+				RowData{Data: []byte{0x02, 0xc1, 0x2b}},
+			},
+		},
+		{
+			name: "unknown message ends the walk",
+			b: []byte{
+				0x00, 0x10, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09, 0x00, 0x00, 0x20, 0x06, 0x00,
+			},
+			want: []TTCMessage{
+				Status{},
+				UnknownMessage{MessageCode: 0x20, Data: []byte{0x06, 0x00}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ctx.ReadMessages(tt.b)
+			if err != nil {
+				t.Errorf("ReadTTCMessages() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadTTCMessages() = \n%#v,\n want \n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMessages_wireLengths(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  TTCContext
+		b    []byte
+	}{
+		{
+			name: "return parameters with a huge count",
+			b: []byte{
+				0x00, 0x10, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x04, 0x7f, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			name: "return parameters with a huge transaction id",
+			b: []byte{
+				0x00, 0x11, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x04, 0x7f, 0xff, 0xff,
+				0xff,
+			},
+		},
+		{
+			name: "xa_start parameters with a huge context",
+			ctx:  TTCContext{Function: OTXSE},
+			b: []byte{
+				0x00, 0x12, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x02, 0xff, 0xff, 0x01,
+				0x02, 0x03,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.ctx.ReadMessages(tt.b)
+			if err != io.ErrUnexpectedEOF {
+				t.Errorf("ReadMessages() error = %v, want %v", err, io.ErrUnexpectedEOF)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package packet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// XID identifies a branch of a global transaction
+type XID struct {
+	FormatId uint32
+	GlobalId []byte // Global transaction id
+	Branch   []byte // Branch qualifier
+}
+
+// String gives the XID as format id, global transaction id and branch qualifier
+func (x XID) String() string {
+	return fmt.Sprintf("%d.%X.%X", x.FormatId, x.GlobalId, x.Branch)
+}
+
+// Operations of the OTXSE call
+const (
+	TxnStart  uint32 = 1 // xa_start
+	TxnDetach uint32 = 2 // xa_end
+)
+
+// Flags of the OTXSE call
+const (
+	TxnNew     uint32 = 0x00000001 // Start a new branch
+	TxnJoin    uint32 = 0x00000002 // Join an existing branch
+	TxnResume  uint32 = 0x00000004 // Resume a suspended branch
+	TxnPromote uint32 = 0x00000008 // Promote the local transaction
+	TxnSuspend uint32 = 0x00100000 // Suspend the branch on end
+)
+
+// Operations of the OTXEN call
+const (
+	TxnCommit  uint32 = 1 // xa_commit
+	TxnAbort   uint32 = 2 // xa_rollback
+	TxnPrepare uint32 = 3 // xa_prepare
+	TxnForget  uint32 = 4 // xa_forget
+)
+
+// States of a transaction branch, asked by the OTXEN call and given back by the server
+const (
+	TxnStatePrepare        uint32 = 0
+	TxnStateRequiresCommit uint32 = 1
+	TxnStateCommitted      uint32 = 2
+	TxnStateAborted        uint32 = 3
+	TxnStateReadOnly       uint32 = 4
+	TxnStateForgotten      uint32 = 5
+)
+
+var txnStateStr = map[uint32]string{
+	TxnStatePrepare:        "prepare",
+	TxnStateRequiresCommit: "requires commit",
+	TxnStateCommitted:      "committed",
+	TxnStateAborted:        "aborted",
+	TxnStateReadOnly:       "read only",
+	TxnStateForgotten:      "forgotten",
+}
+
+// TxnStateString names the state of a transaction branch
+func TxnStateString(s uint32) string {
+	if str, ok := txnStateStr[s]; ok {
+		return str
+	}
+	return fmt.Sprintf("state %d", s)
+}
+
+// TxnSwitch is the client's OTXSE call, it attaches the session to a transaction branch or detaches it
+type TxnSwitch struct {
+	Sequence  uint8
+	Operation uint32 // TxnStart or TxnDetach
+	XID       *XID   // nil when not sent
+	Flags     uint32
+	Timeout   uint32 // Seconds before the branch is rolled back
+	Context   []byte // Transaction context given by the server at start
+}
+
+func (m TxnSwitch) Code() TTCCode { return TTIFUN }
+
+// Call gives the transaction switch function call, without its body
+func (m TxnSwitch) Call() FunctionCall {
+	return FunctionCall{Function: OTXSE, Sequence: m.Sequence}
+}
+
+func readTxnSwitch(buff *bytes.Buffer, call FunctionCall) (TxnSwitch, error) {
+	var err error
+	m := TxnSwitch{Sequence: call.Sequence}
+
+	m.Operation, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	var ctxLen uint32
+	ctxLen, err = readPointerLength(buff)
+	if err != nil {
+		return m, err
+	}
+	var x xidHeader
+	x, err = readXIDHeader(buff)
+	if err != nil {
+		return m, err
+	}
+	m.Flags, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Timeout, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	// Application value, returned context and its length pointers, internal and external names
+	err = SkipUInts(buff, 1, 1, 1, 1, 4, 1, 4)
+	if err != nil {
+		return m, err
+	}
+	m.Context, err = readLocator(buff, int(ctxLen))
+	if err != nil {
+		return m, err
+	}
+	m.XID, err = x.read(buff)
+	if err != nil {
+		return m, err
+	}
+	err = SkipUInts(buff, 4) // Application value
+	return m, err
+}
+
+// TxnChangeState is the client's OTXEN call, it prepares, commits, rolls back or forgets a transaction branch
+type TxnChangeState struct {
+	Sequence  uint8
+	Operation uint32 // TxnCommit, TxnAbort, TxnPrepare or TxnForget
+	XID       *XID   // nil when not sent
+	State     uint32 // State asked for the branch
+	Flags     uint32
+	Context   []byte // Transaction context given by the server at start
+}
+
+func (m TxnChangeState) Code() TTCCode { return TTIFUN }
+
+// Call gives the transaction change state function call, without its body
+func (m TxnChangeState) Call() FunctionCall {
+	return FunctionCall{Function: OTXEN, Sequence: m.Sequence}
+}
+
+func readTxnChangeState(buff *bytes.Buffer, call FunctionCall) (TxnChangeState, error) {
+	var err error
+	m := TxnChangeState{Sequence: call.Sequence}
+
+	m.Operation, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	var ctxLen uint32
+	ctxLen, err = readPointerLength(buff)
+	if err != nil {
+		return m, err
+	}
+	var x xidHeader
+	x, err = readXIDHeader(buff)
+	if err != nil {
+		return m, err
+	}
+	err = SkipUInts(buff, 4) // Timeout
+	if err != nil {
+		return m, err
+	}
+	m.State, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	err = SkipUInts(buff, 1) // Returned state pointer
+	if err != nil {
+		return m, err
+	}
+	m.Flags, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Context, err = readLocator(buff, int(ctxLen))
+	if err != nil {
+		return m, err
+	}
+	m.XID, err = x.read(buff)
+	return m, err
+}
+
+// xidHeader gives the format id and the lengths of the XID, its ids are sent at the end of the call
+type xidHeader struct {
+	formatId  uint32
+	globalLen uint32
+	branchLen uint32
+	length    uint32
+}
+
+func readXIDHeader(buff *bytes.Buffer) (xidHeader, error) {
+	var err error
+	x := xidHeader{}
+	x.formatId, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return x, err
+	}
+	x.globalLen, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return x, err
+	}
+	x.branchLen, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return x, err
+	}
+	x.length, err = readPointerLength(buff)
+	return x, err
+}
+
+// read reads the ids of the XID, nil when the call has no XID
+func (x xidHeader) read(buff *bytes.Buffer) (*XID, error) {
+	if x.length == 0 {
+		return nil, nil
+	}
+	if x.globalLen+x.branchLen != x.length || buff.Len() < int(x.length) {
+		return nil, io.EOF
+	}
+	return &XID{
+		FormatId: x.formatId,
+		GlobalId: buff.Next(int(x.globalLen)),
+		Branch:   buff.Next(int(x.branchLen)),
+	}, nil
+}
+
+// TxnSwitchParameters are returned by the server for an OTXSE call
+type TxnSwitchParameters struct {
+	ApplicationValue uint32
+	Context          []byte // Transaction context, sent back by the following calls on the branch
+}
+
+func (m TxnSwitchParameters) Code() TTCCode { return TTIRPA }
+
+func readTxnSwitchParameters(buff *bytes.Buffer) (TxnSwitchParameters, error) {
+	var err error
+	m := TxnSwitchParameters{}
+	m.ApplicationValue, err = GetUInt(buff, 4, true, true)
+	if err != nil {
+		return m, err
+	}
+	var n uint32
+	n, err = GetUInt(buff, 2, true, true)
+	if err != nil {
+		return m, err
+	}
+	m.Context, err = readLocator(buff, int(n))
+	return m, err
+}
+
+// TxnChangeStateParameters are returned by the server for an OTXEN call
+type TxnChangeStateParameters struct {
+	State uint32 // New state of the branch, like read only after a prepare
+}
+
+func (m TxnChangeStateParameters) Code() TTCCode { return TTIRPA }
+
+func readTxnChangeStateParameters(buff *bytes.Buffer) (TxnChangeStateParameters, error) {
+	var err error
+	m := TxnChangeStateParameters{}
+	m.State, err = GetUInt(buff, 4, true, true)
+	return m, err
+}
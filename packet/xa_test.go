@@ -0,0 +1,82 @@
+package packet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadTxnMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  TTCContext
+		b    []byte
+		want []TTCMessage
+	}{
+		{
+			name: "xa_start call",
+			ctx:  TTCContext{},
+			b: []byte{
+				0x00, 0x29, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x67, 0x07, 0x01, 0x01, 0x00,
+				0x00, 0x01, 0x01, 0x01, 0x02, 0x01, 0x01, 0x01, 0x01, 0x03, 0x01, 0x01, 0x01, 0x3c, 0x01, 0x01,
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x0a, 0x0b, 0x01, 0x00,
+			},
+			want: []TTCMessage{
+				TxnSwitch{
+					Sequence:  7,
+					Operation: TxnStart,
+					XID:       &XID{FormatId: 1, GlobalId: []byte{0x0a, 0x0b}, Branch: []byte{0x01}},
+					Flags:     TxnNew,
+					Timeout:   60,
+				},
+			},
+		},
+		{
+			name: "xa_start response",
+			ctx:  TTCContext{FromServer: true, Function: OTXSE},
+			b: []byte{
+				0x00, 0x31, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x00, 0x01, 0x04, 0xde, 0xad,
+				0xbe, 0xef, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09, 0x00, 0x01,
+				0x05,
+			},
+			want: []TTCMessage{
+				TxnSwitchParameters{Context: []byte{0xde, 0xad, 0xbe, 0xef}},
+				Summary{},
+				Status{Sequence: 5},
+			},
+		},
+		{
+			name: "xa_prepare response",
+			ctx:  TTCContext{FromServer: true, Function: OTXEN},
+			b: []byte{
+				0x00, 0x2c, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x01, 0x01, 0x04, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09, 0x00, 0x01, 0x05,
+			},
+			want: []TTCMessage{
+				TxnChangeStateParameters{State: TxnStateRequiresCommit},
+				Summary{},
+				Status{Sequence: 5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ctx.ReadMessages(tt.b)
+			if err != nil {
+				t.Errorf("ReadMessages() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadMessages() = \n%#v,\n want \n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXID_String(t *testing.T) {
+	x := XID{FormatId: 4660, GlobalId: []byte{0x0a, 0x0b}, Branch: []byte{0x01}}
+	if got, want := x.String(), "4660.0A0B.01"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
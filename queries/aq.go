@@ -0,0 +1,115 @@
+package queries
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/simulot/oracle_trc/packet"
+)
+
+// AQMessage is an Advanced Queuing enqueue or dequeue
+type AQMessage struct {
+	Dequeue     bool
+	Queue       string
+	Consumer    string                     // Consumer of the dequeue
+	Wait        int32                      // Seconds the dequeue waits for a message, -1 for ever
+	Properties  packet.AQMessageProperties // Sent with the enqueue, received with the dequeue
+	PayloadType []byte                     // TOID of the payload type
+	PayloadSize int                        // Bytes of the enqueued payload image
+	MessageId   []byte                     // Given by the server to the enqueued message, or asked by the dequeue
+	Found       bool                       // The dequeue got a message
+}
+
+// newAQMessage gives the message of an enqueue or dequeue call, nil for other calls
+func newAQMessage(c packet.Caller) *AQMessage {
+	switch c := c.(type) {
+	case packet.AQEnqueue:
+		return &AQMessage{
+			Queue:       c.Queue,
+			Properties:  c.Properties,
+			PayloadType: c.PayloadType,
+			PayloadSize: len(c.Payload),
+		}
+	case packet.AQDequeue:
+		return &AQMessage{
+			Dequeue:     true,
+			Queue:       c.Queue,
+			Consumer:    c.Consumer,
+			Wait:        c.Wait,
+			Properties:  packet.AQMessageProperties{Correlation: c.Correlation},
+			PayloadType: c.PayloadType,
+			MessageId:   c.MessageId,
+		}
+	}
+	return nil
+}
+
+// received completes the message with the server's response
+func (a *AQMessage) received(r *Response) {
+	if r.AQMessageId != nil {
+		a.MessageId = r.AQMessageId
+	}
+	if r.Dequeued != nil {
+		a.Found = true
+		a.Properties = *r.Dequeued
+	}
+}
+
+// String describes the operation and the message
+func (a AQMessage) String() string {
+	sb := strings.Builder{}
+	if a.Dequeue {
+		sb.WriteString("AQ dequeue on ")
+	} else {
+		sb.WriteString("AQ enqueue on ")
+	}
+	sb.WriteString(a.Queue)
+	if a.Consumer != "" {
+		sb.WriteString(" for consumer ")
+		sb.WriteString(a.Consumer)
+	}
+	if a.Dequeue {
+		switch {
+		case a.Wait < 0:
+			sb.WriteString(", wait forever")
+		case a.Wait > 0:
+			sb.WriteString(fmt.Sprintf(", wait %ds", a.Wait))
+		}
+	}
+	l := []string{}
+	if !a.Dequeue || a.Found {
+		l = append(l, fmt.Sprintf("priority %d", a.Properties.Priority), fmt.Sprintf("delay %d", a.Properties.Delay))
+	}
+	if a.Properties.Correlation != "" {
+		l = append(l, "correlation '"+strings.Replace(a.Properties.Correlation, "'", "''", -1)+"'")
+	}
+	if t := a.payloadTypeString(); t != "" {
+		if a.Dequeue {
+			l = append(l, t+" payload")
+		} else {
+			l = append(l, fmt.Sprintf("%s payload of %d bytes", t, a.PayloadSize))
+		}
+	}
+	if a.MessageId != nil {
+		l = append(l, fmt.Sprintf("message id %X", a.MessageId))
+	}
+	if len(l) > 0 {
+		sb.WriteString(": ")
+		sb.WriteString(strings.Join(l, ", "))
+	}
+	return sb.String()
+}
+
+// payloadTypeString names the payload type, object types are given by their TOID
+func (a AQMessage) payloadTypeString() string {
+	switch {
+	case len(a.PayloadType) == 0:
+		return ""
+	case bytes.Equal(a.PayloadType, packet.AQRawTOID):
+		return "RAW"
+	case bytes.Equal(a.PayloadType, packet.AQJsonTOID):
+		return "JSON"
+	}
+	return fmt.Sprintf("object type %X", a.PayloadType)
+}
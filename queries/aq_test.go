@@ -0,0 +1,133 @@
+package queries
+
+import (
+	"testing"
+
+	"github.com/simulot/oracle_trc/packet"
+)
+
+func Test_aqEnqueueDequeue(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=129.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=129
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 81 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 03 79 05 01 01 0C  |...y....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 02 00 81 01 01 06 06  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 4F 52 44 2D 34 32 00 00  |ORD-42..|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 01 04 0E 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 40 00 00 01 41 00 01  |.@...A..|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 01 42 00 00 01  |....B...|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 45 00 00 00 00 00 00 01  |E.......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 02 00 00 00 01 01 10 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 01 10 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00 00 0C 41  |.......A|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 50 2E 4F 52 44 45 52  |PP.ORDER|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 53 5F 51 00 00 00 00 00  |S_Q.....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 17 05 68 65 6C 6C  |....hell|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 6F                       |o       |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: socket 1288 had bytes read=58
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 3A 00 00 06 00 00 00  |.:......|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 08 10 11 12 13 14  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 15 16 17 18 19 1A 1B 1C  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 1D 1E 1F 04 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 09 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 01 05                    |..      |
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: exit: oln=0, dln=48, tot=58, rc=0
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: tot=0, plen=85.
+(5236) [22-OCT-2020 12:44:14:752] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfpwr: socket 1288 had bytes written=85
+(5236) [22-OCT-2020 12:44:14:752] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 00 55 00 00 06 00 00 00  |.U......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 00 00 03 7A 06 01 01 0C  |...z....|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 01 01 01 01 01 01 07 01  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 03 01 03 01 02 01 0A 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 00 00 00 01 01 10 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 01 01 10 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 0C 41 50 50 2E 4F 52 44  |.APP.ORD|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 45 52 53 5F 51 07 42 49  |ERS_Q.BI|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 4C 4C 49 4E 47 00 00 00  |LLING...|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 00 00 00 00 17           |.....   |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: socket 1288 had bytes read=60
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 3C 00 00 06 00 00 00  |.<......|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 08 01 30 01 02 00  |....0...|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 81 01 01 06 06 4F 52 44  |.....ORD|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 2D 34 32 00 00 00 00 00  |-42.....|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 01 04 0E 00 00 01 40 00  |......@.|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 01 41 00 01 01 01 00  |..A.....|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 01 42 00 00 01 45 00 00  |.B...E..|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 AA BB              |....    |
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: exit: oln=0, dln=50, tot=60, rc=0
+
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	want := []struct {
+		function packet.FunctionCode
+		event    string
+	}{
+		{packet.OAQEQ, "AQ enqueue on APP.ORDERS_Q: priority 2, delay 0, correlation 'ORD-42', RAW payload of 6 bytes, message id 101112131415161718191A1B1C1D1E1F"},
+		{packet.OAQDQ, "AQ dequeue on APP.ORDERS_Q for consumer BILLING, wait 10s: priority 2, delay 0, correlation 'ORD-42', RAW payload"},
+	}
+	if len(got) != len(want) {
+		t.Errorf("Number of queries = %d, want %d", len(got), len(want))
+		return
+	}
+	for i, w := range want {
+		if got[i].Function != w.function || got[i].Event != w.event {
+			t.Errorf("Query %d = %s %q, want %s %q", i, got[i].Function, got[i].Event, w.function, w.event)
+		}
+		if got[i].Err != nil {
+			t.Errorf("Query %d error = %v", i, got[i].Err)
+		}
+	}
+}
+
+func TestAQMessage_String(t *testing.T) {
+	tests := []struct {
+		name string
+		a    AQMessage
+		want string
+	}{
+		{
+			name: "dequeue without message",
+			a:    AQMessage{Dequeue: true, Queue: "Q", Wait: -1},
+			want: "AQ dequeue on Q, wait forever",
+		},
+		{
+			name: "object payload",
+			a:    AQMessage{Queue: "Q", PayloadType: []byte{0xA1, 0xB2}, PayloadSize: 40, Properties: packet.AQMessageProperties{Priority: 1, Delay: 60}},
+			want: "AQ enqueue on Q: priority 1, delay 60, object type A1B2 payload of 40 bytes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package queries
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/simulot/oracle_trc/packet"
+)
+
+// placeholder is a bind placeholder of a statement, like :1 or :name
+type placeholder struct {
+	Name string // Name without the colon
+	Pos  int    // Offset of the colon in the statement
+}
+
+// placeholders lists the bind placeholders of the statement in order.
+// Colons in literals, quoted identifiers and comments are skipped, like the PL/SQL assignment :=.
+func placeholders(stmt string) []placeholder {
+	l := []placeholder{}
+	for i := 0; i < len(stmt); i++ {
+		switch {
+		case stmt[i] == '\'' || stmt[i] == '"':
+			end := strings.IndexByte(stmt[i+1:], stmt[i])
+			if end < 0 {
+				return l
+			}
+			i += end + 1
+		case strings.HasPrefix(stmt[i:], "--"):
+			end := strings.IndexByte(stmt[i:], '\n')
+			if end < 0 {
+				return l
+			}
+			i += end
+		case strings.HasPrefix(stmt[i:], "/*"):
+			end := strings.Index(stmt[i+2:], "*/")
+			if end < 0 {
+				return l
+			}
+			i += end + 3
+		case stmt[i] == ':':
+			j := i + 1
+			for j < len(stmt) && isNameChar(stmt[j]) {
+				j++
+			}
+			if j > i+1 {
+				l = append(l, placeholder{Name: stmt[i+1 : j], Pos: i})
+				i = j - 1
+			}
+		}
+	}
+	return l
+}
+
+// isNameChar checks if the character may be part of a placeholder name
+func isNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '$' || c == '#'
+}
+
+// nameBinds gives the binds the name of their placeholder.
+// PL/SQL binds a value per name, in order of first appearance. SQL statements may bind a value per placeholder.
+// Binds that don't match the placeholders are left unnamed.
+func (q *Query) nameBinds() {
+	all := placeholders(q.Query)
+	names := []string{}
+	seen := map[string]bool{}
+	for _, p := range all {
+		// Unquoted names are case insensitive
+		n := string(toUpperAscii([]byte(p.Name)))
+		if !seen[n] {
+			seen[n] = true
+			names = append(names, p.Name)
+		}
+	}
+	switch len(q.Params) {
+	case len(names):
+	case len(all):
+		names = names[:0]
+		for _, p := range all {
+			names = append(names, p.Name)
+		}
+	default:
+		return
+	}
+	for i, p := range q.Params {
+		p.Name = names[i]
+	}
+}
+
+// bindName gives the placeholder of the bind, its position when its name isn't known
+func (p ParameterInfo) bindName(i int) string {
+	if p.Name != "" {
+		return ":" + p.Name
+	}
+	return ":" + strconv.Itoa(i+1)
+}
+
+var (
+	// plsqlCall finds the stored procedure or function called by a block or a CALL statement
+	plsqlCall  = regexp.MustCompile(`(?is)^\s*(?:BEGIN\s+(?::[\w$#]+\s*:=\s*)?([\w$#."]+)\s*(?:\([^;]*\))?\s*;\s*END\s*;?|CALL\s+([\w$#."]+)\s*(?:\([^;]*\))?)\s*$`)
+	plsqlBlock = regexp.MustCompile(`(?is)^\s*(BEGIN|DECLARE)\b`)
+)
+
+// plsqlKeyWords are statements of blocks that look like calls
+var plsqlKeyWords = map[string]bool{
+	"NULL":     true,
+	"COMMIT":   true,
+	"ROLLBACK": true,
+}
+
+// plsqlKind tells what PL/SQL the statement executes, an empty string for SQL statements
+func plsqlKind(stmt string) string {
+	if m := plsqlCall.FindStringSubmatch(stmt); m != nil {
+		name := m[1] + m[2]
+		if !plsqlKeyWords[string(toUpperAscii([]byte(name)))] {
+			return "call of " + name
+		}
+	}
+	if plsqlBlock.MatchString(stmt) {
+		return "anonymous block"
+	}
+	return ""
+}
+
+// dmlReturning finds the INTO keyword of the RETURNING clause of a DML statement
+var dmlReturning = regexp.MustCompile(`(?is)^\s*(INSERT|UPDATE|DELETE|MERGE)\b.*\bRETURNING\b.*\b(INTO)\b`)
+
+// returningBinds gives the number of binds of the RETURNING INTO clause, they are the last ones
+func returningBinds(stmt string) int {
+	m := dmlReturning.FindStringSubmatchIndex(stmt)
+	if m == nil {
+		return 0
+	}
+	n := 0
+	for _, p := range placeholders(stmt) {
+		if p.Pos > m[4] {
+			n++
+		}
+	}
+	return n
+}
+
+// setDirections gives the binds the direction declared by their metadata.
+// SQL statements receive values only through the RETURNING INTO clause, its binds are also found in the statement.
+// The IO vector of the response confirms the directions.
+func (q *Query) setDirections() {
+	ret := len(q.Params) - returningBinds(q.Query)
+	for i, p := range q.Params {
+		switch {
+		case i >= ret:
+			p.Direction = RetVal
+		case p.Flag&bindOutput != 0 && q.PLSQL == "":
+			p.Direction = RetVal
+		case p.Flag&bindOutput != 0:
+			p.Direction = Output
+		default:
+			p.Direction = Input
+		}
+	}
+}
+
+// bindColumns gives what the decoding of the values sent back for the binds needs
+func (q *Query) bindColumns() []packet.Column {
+	cols := make([]packet.Column, len(q.Params))
+	for i, p := range q.Params {
+		cols[i] = packet.Column{DataType: uint8(p.DataType)}
+	}
+	return cols
+}
+
+// returningColumns gives the binds of the RETURNING INTO clause, in binds order
+func (q *Query) returningColumns() []packet.Column {
+	cols := []packet.Column{}
+	for _, p := range q.Params {
+		if p.Direction == RetVal {
+			cols = append(cols, packet.Column{DataType: uint8(p.DataType)})
+		}
+	}
+	return cols
+}
+
+// setOutBinds confirms the directions of the binds with the IO vector, when the server sent it, and gives them the values sent back
+func (q *Query) setOutBinds() {
+	r := q.Response
+	for i, d := range r.Directions {
+		if i >= len(q.Params) || q.Params[i].Direction == RetVal {
+			continue
+		}
+		switch {
+		case d&packet.BindIn != 0 && d&packet.BindOut != 0:
+			q.Params[i].Direction = InOut
+		case d&packet.BindOut != 0:
+			q.Params[i].Direction = Output
+		default:
+			q.Params[i].Direction = Input
+		}
+	}
+	i := 0
+	for _, p := range q.Params {
+		if p.Direction == Input {
+			continue
+		}
+		if i < len(r.OutValues) {
+			p.OutValues = r.OutValues[i]
+		}
+		i++
+	}
+}
+
+// outString gives the values sent back for the bind, separated by commas
+func (p ParameterInfo) outString() string {
+	values := make([]string, len(p.OutValues))
+	for i, v := range p.OutValues {
+		o := p
+		o.Elements, o.Value, o.IsNull, o.Lob = nil, v, v == nil, nil
+		values[i] = o.quoted()
+	}
+	return strings.Join(values, ", ")
+}
+
+// reuseBinds gives the query the binds of the previous execution of the cursor, without their values
+func (q *Query) reuseBinds(cached *Query) {
+	q.Params = make([]*ParameterInfo, len(cached.Params))
+	for i, cp := range cached.Params {
+		np := *cp
+		np.Value = nil
+		np.Elements = nil
+		np.OutValues = nil
+		np.Lob = nil
+		q.Params[i] = &np
+	}
+}
+
+// readBindRows reads the values of the binds sent after the call.
+// Array DML sends a row of values per iteration, the rows read before an error are kept.
+func (q *Query) readBindRows(buff *bytes.Buffer, iterations int) error {
+	// Skip byte 7
+	_, err := buff.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range q.Params {
+		err = readBindValue(buff, p)
+		if err != nil {
+			return err
+		}
+	}
+	q.BindRows = [][]*ParameterInfo{q.Params}
+
+	// Array DML sends a row of values per iteration
+	for len(q.BindRows) < iterations && buff.Len() > 0 && buff.Bytes()[0] == byte(packet.TTIRXD) {
+		buff.Next(1)
+		row := make([]*ParameterInfo, len(q.Params))
+		for i, p := range q.Params {
+			np := *p
+			err = readBindValue(buff, &np)
+			if err != nil {
+				// The rows read so far are kept
+				return nil
+			}
+			row[i] = &np
+		}
+		q.BindRows = append(q.BindRows, row)
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package queries
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/simulot/oracle_trc/packet"
+)
+
+func Test_placeholders(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want []placeholder
+	}{
+		{stmt: "SELECT * FROM t WHERE a = :1 AND b = :name", want: []placeholder{{Name: "1", Pos: 26}, {Name: "name", Pos: 37}}},
+		{stmt: "BEGIN :x := 'a:b'; END;", want: []placeholder{{Name: "x", Pos: 6}}},
+		{stmt: "SELECT 1 /* :c */ FROM \"T:a\" -- :d\nWHERE x = :e", want: []placeholder{{Name: "e", Pos: 45}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.stmt, func(t *testing.T) {
+			if got := placeholders(tt.stmt); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("placeholders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_nameBinds(t *testing.T) {
+	tests := []struct {
+		name   string
+		stmt   string
+		params int
+		want   []string
+	}{
+		{name: "PL/SQL, value per name", stmt: "BEGIN p(:p_id, :P_NAME, :p_id); END;", params: 2, want: []string{"p_id", "P_NAME"}},
+		{name: "SQL, value per placeholder", stmt: "SELECT * FROM t WHERE a = :x OR b = :x", params: 2, want: []string{"x", "x"}},
+		{name: "positional", stmt: "SELECT * FROM t WHERE a = :1", params: 1, want: []string{"1"}},
+		{name: "binds not matching", stmt: "SELECT * FROM t WHERE a = :1", params: 2, want: []string{"", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Query{Query: tt.stmt}
+			for i := 0; i < tt.params; i++ {
+				q.Params = append(q.Params, &ParameterInfo{})
+			}
+			q.nameBinds()
+			got := []string{}
+			for _, p := range q.Params {
+				got = append(got, p.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nameBinds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_plsqlKind(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want string
+	}{
+		{stmt: "BEGIN pkg_orders.create_order(:p_customer_id, :p_amount); END;", want: "call of pkg_orders.create_order"},
+		{stmt: "begin :r := get_count(:p_name); end;", want: "call of get_count"},
+		{stmt: "CALL refresh_stats()", want: "call of refresh_stats"},
+		{stmt: "BEGIN a(1); b(2); END;", want: "anonymous block"},
+		{stmt: "DECLARE n NUMBER; BEGIN n := 1; END;", want: "anonymous block"},
+		{stmt: "BEGIN NULL; END;", want: "anonymous block"},
+		{stmt: "SELECT 1 FROM dual", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.stmt, func(t *testing.T) {
+			if got := plsqlKind(tt.stmt); got != tt.want {
+				t.Errorf("plsqlKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_setDirections(t *testing.T) {
+	tests := []struct {
+		name       string
+		stmt       string
+		flags      []uint8
+		directions []byte // IO vector, nil when the server doesn't send it
+		want       []ParameterDirection
+	}{
+		{name: "PL/SQL OUT bind from the metadata", stmt: "BEGIN :1 := get_count(:2); END;", flags: []uint8{0x83, 0x03}, want: []ParameterDirection{Output, Input}},
+		{name: "RETURNING INTO from the metadata", stmt: "INSERT INTO t (a) VALUES (:1) RETURNING id INTO :2", flags: []uint8{0x03, 0x83}, want: []ParameterDirection{Input, RetVal}},
+		{name: "output bind of a SQL statement", stmt: "INSERT INTO t (a) VALUES (:1) /* RETURNING */", flags: []uint8{0x03, 0x83}, want: []ParameterDirection{Input, RetVal}},
+		{name: "RETURNING INTO from the statement", stmt: "UPDATE t SET a = :1 RETURNING id INTO :2", flags: []uint8{0x03, 0x03}, want: []ParameterDirection{Input, RetVal}},
+		{
+			name:       "IO vector confirms IN OUT",
+			stmt:       "BEGIN p(:1, :2); END;",
+			flags:      []uint8{0x83, 0x03},
+			directions: []byte{packet.BindIn | packet.BindOut, packet.BindIn},
+			want:       []ParameterDirection{InOut, Input},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Query{Query: tt.stmt, Response: &Response{Directions: tt.directions}}
+			for _, f := range tt.flags {
+				q.Params = append(q.Params, &ParameterInfo{Flag: f})
+			}
+			q.PLSQL = plsqlKind(q.Query)
+			q.setDirections()
+			q.setOutBinds()
+			got := []ParameterDirection{}
+			for _, p := range q.Params {
+				got = append(got, p.Direction)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("directions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_returningBinds(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want int
+	}{
+		{stmt: "INSERT INTO t (a, b) VALUES (:1, :2) RETURNING id, c INTO :3, :4", want: 2},
+		{stmt: "update t set a = :a where b = :b returning a into :r", want: 1},
+		{stmt: "SELECT a INTO :x FROM t", want: 0},
+		{stmt: "BEGIN INSERT INTO t VALUES (:1) RETURNING id INTO :2; END;", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.stmt, func(t *testing.T) {
+			if got := returningBinds(tt.stmt); got != tt.want {
+				t.Errorf("returningBinds() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outBinds(t *testing.T) {
+	tests := []struct {
+		name       string
+		trc        string
+		directions []ParameterDirection
+		want       string
+	}{
+		{
+			name: "PL/SQL OUT bind",
+			trc: `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=128.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=128
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 80 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 03 5E 16 02 80 29  |...^...)|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 02  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 1F  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 42 45 47 49 4E 20 3A 31  |BEGIN.:1|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 20 3A 3D 20 67 65 74 5F  |.:=.get_|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 63 6F 75 6E 74 28 3A 32  |count(:2|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 29 3B 20 45 4E 44 3B 01  |);.END;.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 00 00 00 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 00 00 00 00 02 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 01 16 00 01 10 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 60 00 00 00  |....` + "`" + `...|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 14 00 01 10 00 00 02  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 03 69 01 00 07 00 01 78  |.i.....x|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: socket 1288 had bytes read=59
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 3B 00 00 06 00 00 00  |.;......|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 0B 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 01 02 02 10 20 00 07 02  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: C1 2B 04 01 01 00 00 00  |.+......|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 01 05 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 09  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 01 17                 |...     |
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: exit: oln=0, dln=49, tot=59, rc=0
+`,
+			directions: []ParameterDirection{Output, Input},
+			want:       "  PL/SQL call of get_count\n  :1 => 42\n  :2 = 'x'\n",
+		},
+		{
+			name: "RETURNING INTO",
+			trc: `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=173.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=173
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 AD 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 03 5E 16 02 80 29  |...^...)|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 02  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 4C  |.......L|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 49 4E 53 45 52 54 20 49  |INSERT.I|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 4E 54 4F 20 65 66 6C 6F  |NTO.eflo|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 77 5F 70 61 72 61 6D 73  |w_params|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 20 28 70 61 72 61 6D 5F  |.(param_|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 6E 61 6D 65 29 20 56 41  |name).VA|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 4C 55 45 53 20 28 3A 31  |LUES.(:1|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 29 20 52 45 54 55 52 4E  |).RETURN|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 49 4E 47 20 70 61 72 61  |ING.para|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 6D 5F 69 64 20 49 4E 54  |m_id.INT|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 4F 20 3A 32 01 01 00 00  |O.:2....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 60 00 00 00 01  |...` + "`" + `....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 14 00 01 10 00 00 02 03  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 69 01 00 02 00 00 00 01  |i.......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 16 00 01 10 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 07 01 78 00           |...x.   |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: socket 1288 had bytes read=49
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 31 00 00 06 00 00 00  |.1......|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 07 01 01 02 C1 2B  |.......+|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 04 01 01 00 00 00 01 05  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 09 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 17                       |.       |
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: exit: oln=0, dln=39, tot=49, rc=0
+`,
+			directions: []ParameterDirection{Input, RetVal},
+			want:       "  :1 = 'x'\n  :2 => 42\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getQueriesFromTraceSnippet(tt.trc)
+			if err != nil {
+				t.Errorf("Error returned error = %v", err)
+				return
+			}
+			if len(got) != 1 || len(got[0].Params) != len(tt.directions) {
+				t.Errorf("Number of queries = %d, want 1 with %d binds", len(got), len(tt.directions))
+				return
+			}
+			q := got[0]
+			for i, p := range q.Params {
+				if p.Direction != tt.directions[i] {
+					t.Errorf("Direction of :%d = %d, want %d", i+1, p.Direction, tt.directions[i])
+				}
+			}
+			if s := q.String(); !strings.Contains(s, tt.want) {
+				t.Errorf("String() = %s, want %s", s, tt.want)
+			}
+		})
+	}
+}
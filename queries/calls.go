@@ -0,0 +1,370 @@
+package queries
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/simulot/oracle_trc/packet"
+)
+
+// maxAL8I4 bounds the number of ints of the AL8I4 structure, larger values come from a wrong layout
+const maxAL8I4 = 64
+
+// TTC versions that add fields to the OALL8 call
+const (
+	ttc122     uint8 = 8  // 12.2: row counts of array DML
+	ttc122Ext1 uint8 = 9  // 12.2 EXT1: SQL signature and SQL id
+	ttc181Ext1 uint8 = 11 // 18.1 EXT1: sharding chunk ids
+)
+
+// oall8Versions are the versions with a distinct OALL8 layout, from the oldest
+var oall8Versions = []uint8{0, ttc122, ttc122Ext1, ttc181Ext1}
+
+// oall8Extensions are the fields added to the OALL8 call after 11g
+type oall8Extensions struct {
+	rowCounts bool   // The client asks the rows processed by each iteration of array DML
+	signature uint32 // Length of the SQL signature, 0 when it isn't sent
+	sqlId     uint32 // Length of the SQL id, 0 when it isn't sent
+	chunkIds  uint32 // Number of sharding chunk ids, 0 when they aren't sent
+}
+
+// readOALL8Extensions reads the fields added to the OALL8 call after 11g, up to the statement.
+// Only pointers and lengths are read, the contents are sent after the AL8I4 structure.
+func readOALL8Extensions(buff *bytes.Buffer, version uint8) (oall8Extensions, error) {
+	var err error
+	e := oall8Extensions{}
+	if version >= ttc122 {
+		var b byte
+		b, err = buff.ReadByte() // Row counts pointer
+		if err != nil {
+			return e, err
+		}
+		e.rowCounts = b != 0
+		err = packet.SkipUInts(buff, 4, 1) // Row counts size and length pointer
+		if err != nil {
+			return e, err
+		}
+	}
+	if version >= ttc122Ext1 {
+		e.signature, err = readPointedLength(buff) // SQL signature
+		if err != nil {
+			return e, err
+		}
+		e.sqlId, err = readPointedLength(buff) // SQL id
+		if err != nil {
+			return e, err
+		}
+		err = packet.SkipUInts(buff, 1) // SQL id length pointer
+		if err != nil {
+			return e, err
+		}
+	}
+	if version >= ttc181Ext1 {
+		e.chunkIds, err = readPointedLength(buff) // Chunk ids
+		if err != nil {
+			return e, err
+		}
+	}
+	return e, nil
+}
+
+// readPointedLength reads a pointer followed by the length of its content, the length is 0 for a null pointer
+func readPointedLength(buff *bytes.Buffer) (uint32, error) {
+	p, err := buff.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	n, err := packet.GetUInt(buff, 4, true, true)
+	if err != nil || p == 0 {
+		return 0, err
+	}
+	return n, nil
+}
+
+// skipContents skips the contents of the extension fields, sent after the AL8I4 structure
+func (e oall8Extensions) skipContents(buff *bytes.Buffer) error {
+	for _, n := range []uint32{e.signature, e.sqlId} {
+		if int(n) > buff.Len() {
+			return io.ErrUnexpectedEOF
+		}
+		buff.Next(int(n))
+	}
+	for i := 0; i < int(e.chunkIds); i++ {
+		_, err := packet.GetUInt(buff, 4, true, true)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// oall8Version gives the TTC version driving the layout of the session's OALL8 calls, the negotiated one.
+// When the negotiation isn't in the trace, the layout is the one leading to the statement of the first call,
+// and it is kept for the next ones.
+func (s *Session) oall8Version(buff *bytes.Buffer, hasStatement bool) uint8 {
+	if v := s.knownTTCVersion(); v > 0 {
+		return v
+	}
+	if s.hasLayout || !hasStatement {
+		return s.layout
+	}
+	for _, c := range oall8Versions {
+		if leadsToStatement(buff, c) {
+			s.layout, s.hasLayout = c, true
+			return c
+		}
+	}
+	return 0
+}
+
+// leadsToStatement checks if a statement follows the fields of the layout
+func leadsToStatement(buff *bytes.Buffer, version uint8) bool {
+	b := bytes.NewBuffer(buff.Bytes())
+	if _, err := readOALL8Extensions(b, version); err != nil {
+		return false
+	}
+	stmt, err := packet.ReadBytes(b)
+	return err == nil && isStatement(stmt)
+}
+
+// statementKeyWords start SQL statements and PL/SQL blocks
+var statementKeyWords = map[string]bool{
+	"SELECT":    true,
+	"WITH":      true,
+	"INSERT":    true,
+	"UPDATE":    true,
+	"DELETE":    true,
+	"MERGE":     true,
+	"BEGIN":     true,
+	"DECLARE":   true,
+	"CALL":      true,
+	"LOCK":      true,
+	"SET":       true,
+	"SAVEPOINT": true,
+	"EXPLAIN":   true,
+	"COMMIT":    true,
+	"ROLLBACK":  true,
+}
+
+// isStatement checks if the text starts like a SQL statement or a PL/SQL block, after comments and parenthesis
+func isStatement(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	t := string(b)
+	for {
+		t = strings.TrimLeft(t, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(t, "--"):
+			end := strings.IndexByte(t, '\n')
+			if end < 0 {
+				return false
+			}
+			t = t[end+1:]
+			continue
+		case strings.HasPrefix(t, "/*"):
+			end := strings.Index(t, "*/")
+			if end < 0 {
+				return false
+			}
+			t = t[end+2:]
+			continue
+		}
+		break
+	}
+	end := strings.IndexFunc(t, func(r rune) bool { return r > 127 || !isNameChar(byte(r)) })
+	if end < 0 {
+		end = len(t)
+	}
+	w := string(toUpperAscii([]byte(t[:end])))
+	return statementKeyWords[w] || ddlKeyWords[w]
+}
+
+// parseV7Call decodes the calls of V7 clients.
+// OALL7 parses, binds and executes a statement like OALL8, with the fields of the V7 layout.
+// OSQL7 parses and executes a statement, it never carries binds. OPARSE parses a statement on the cursor opened by the client,
+// OEXEC executes the statement parsed on a cursor with new values for its binds.
+func (p *Parser) parseV7Call(s *Session, q *Query, call packet.FunctionCall) {
+	buff := bytes.NewBuffer(call.Body)
+	var err error
+	iterations := 1
+	switch call.Function {
+	case packet.OALL7:
+		iterations, err = readOALL7(buff, q)
+	case packet.OSQL7:
+		err = readOSQL7(buff, q)
+	case packet.OPARSE:
+		err = readOPARSE(buff, q)
+	case packet.OEXEC:
+		iterations, err = readOEXEC(buff, q)
+	}
+	if err != nil {
+		return
+	}
+	// A new call on the cursor ends the fetches of the previous execution
+	p.endFetching(s, q.CursorId)
+
+	if q.Len > 0 {
+		var stmt []byte
+		stmt, err = packet.ReadBytes(buff)
+		if err != nil {
+			return
+		}
+		q.Query = string(stmt)
+	}
+
+	for i := 0; i < int(q.ParamLen); i++ {
+		var p *ParameterInfo
+		p, err = GetParamInfo(buff)
+		if err != nil {
+			return
+		}
+		p.WireType = s.wireType(p.DataType)
+		q.Params = append(q.Params, p)
+	}
+
+	if q.Len == 0 {
+		cached := s.cursors[q.CursorId]
+		if cached == nil {
+			// Cursor opened before the beginning of the trace
+			return
+		}
+		q.Query = cached.Query
+		q.Reexecuted = true
+		if q.ParamLen == 0 && (call.Function == packet.OEXEC || q.ExeOp&exeOpBind != 0) {
+			// Binds are the same as the previous execution, only values are sent
+			q.reuseBinds(cached)
+		}
+	}
+
+	if len(q.Params) > 0 {
+		err = q.readBindRows(buff, iterations)
+		if err != nil {
+			return
+		}
+	}
+
+	q.PLSQL = plsqlKind(q.Query)
+	q.nameBinds()
+	q.setDirections()
+	if call.Function == packet.OPARSE && q.CursorId != 0 {
+		// The cursor is given by the client, the next calls on it execute the statement
+		s.cursors[q.CursorId] = q
+	}
+	s.pending = q
+}
+
+// readOALL7 reads the fields of the OALL7 call up to the statement, it gives the number of iterations
+func readOALL7(buff *bytes.Buffer, q *Query) (int, error) {
+	var err error
+
+	// Execution options, the same as OALL8
+	q.ExeOp, err = packet.GetUInt(buff, 4, true, true)
+	if err != nil {
+		return 0, err
+	}
+
+	// Cursor id, 0 when the statement is parsed on a new cursor
+	q.CursorId, err = packet.GetUInt(buff, 4, true, true)
+	if err != nil {
+		return 0, err
+	}
+
+	// Statement pointer and length, the length is 0 for the re-execution of a cursor
+	err = packet.SkipUInts(buff, 1)
+	if err != nil {
+		return 0, err
+	}
+	q.Len, err = packet.GetUInt(buff, 4, true, true)
+	if err != nil {
+		return 0, err
+	}
+
+	// Binds pointer and number of binds
+	err = packet.SkipUInts(buff, 1)
+	if err != nil {
+		return 0, err
+	}
+	q.ParamLen, err = packet.GetUInt(buff, 4, true, true)
+	if err != nil {
+		return 0, err
+	}
+
+	// Defines pointer and number of defines
+	err = packet.SkipUInts(buff, 1, 4)
+	if err != nil {
+		return 0, err
+	}
+
+	// Iterations and rows to fetch
+	var iterations uint32
+	iterations, err = packet.GetUInt(buff, 4, true, true)
+	if err != nil {
+		return 0, err
+	}
+	q.RowToFetch, err = packet.GetUInt(buff, 4, true, true)
+	if err != nil {
+		return 0, err
+	}
+	return int(iterations), nil
+}
+
+// readOSQL7 reads the fields of the OSQL7 call up to the statement.
+// The call ends with the statement, it never carries binds: statements with binds are sent by OALL7 calls.
+func readOSQL7(buff *bytes.Buffer, q *Query) error {
+	var err error
+
+	// Cursor id, 0 when the statement is parsed on a new cursor
+	q.CursorId, err = packet.GetUInt(buff, 4, true, true)
+	if err != nil {
+		return err
+	}
+
+	// Statement pointer and length
+	err = packet.SkipUInts(buff, 1)
+	if err != nil {
+		return err
+	}
+	q.Len, err = packet.GetUInt(buff, 4, true, true)
+	return err
+}
+
+// readOPARSE reads the fields of the OPARSE call up to the statement
+func readOPARSE(buff *bytes.Buffer, q *Query) error {
+	var err error
+
+	// Cursor id, opened by the client before the call
+	q.CursorId, err = packet.GetUInt(buff, 4, true, true)
+	if err != nil {
+		return err
+	}
+
+	// Statement pointer and length
+	err = packet.SkipUInts(buff, 1)
+	if err != nil {
+		return err
+	}
+	q.Len, err = packet.GetUInt(buff, 4, true, true)
+	return err
+}
+
+// readOEXEC reads the fields of the OEXEC call up to the bind values, it gives the number of iterations
+func readOEXEC(buff *bytes.Buffer, q *Query) (int, error) {
+	var err error
+
+	// Cursor id
+	q.CursorId, err = packet.GetUInt(buff, 4, true, true)
+	if err != nil {
+		return 0, err
+	}
+
+	// Iterations
+	var iterations uint32
+	iterations, err = packet.GetUInt(buff, 4, true, true)
+	if err != nil {
+		return 0, err
+	}
+	return int(iterations), nil
+}
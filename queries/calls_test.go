@@ -0,0 +1,277 @@
+package queries
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/simulot/oracle_trc/packet"
+	"github.com/simulot/oracle_trc/trc"
+)
+
+func Test_callVariants(t *testing.T) {
+	oall8Insert122 := []byte{
+		0x00, 0x59, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x5e, 0x16, 0x01, 0x21, 0x00,
+		0x01, 0x01, 0x18, 0x01, 0x01, 0x0d, 0x00, 0x00, 0x00, 0x01, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x01, 0x03,
+		0x01, 0x18, 0x49, 0x4e, 0x53, 0x45, 0x52, 0x54, 0x20, 0x49, 0x4e, 0x54, 0x4f, 0x20, 0x74, 0x20,
+		0x56, 0x41, 0x4c, 0x55, 0x45, 0x53, 0x20, 0x28, 0x31, 0x29, 0x01, 0x01, 0x01, 0x03, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+	tests := []struct {
+		name    string
+		version uint8             // Negotiated TTC version, 0 when the negotiation isn't in the trace
+		cursors map[uint32]*Query // Statements parsed on cursors before the calls
+		calls   [][]byte
+		want    *Query   // Query of the last call, nil when the call isn't decoded
+		values  []string // Values of its binds
+	}{
+		{
+			name: "OALL8 11g layout, negotiation not traced",
+			calls: [][]byte{{
+				0x00, 0x4e, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x5e, 0x16, 0x01, 0x21, 0x00,
+				0x01, 0x01, 0x11, 0x01, 0x01, 0x0d, 0x00, 0x00, 0x00, 0x01, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x11, 0x53, 0x45,
+				0x4c, 0x45, 0x43, 0x54, 0x20, 0x2a, 0x20, 0x46, 0x52, 0x4f, 0x4d, 0x20, 0x65, 0x6d, 0x70, 0x01,
+				0x01, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			}},
+			want: &Query{Function: packet.OALL8, Query: "SELECT * FROM emp"},
+		},
+		{
+			name:    "OALL8 12.2 layout with row counts",
+			version: 8,
+			calls:   [][]byte{oall8Insert122},
+			want:    &Query{Function: packet.OALL8, Query: "INSERT INTO t VALUES (1)", RowCounts: true},
+		},
+		{
+			name:    "OALL8 12.2 EXT1 layout",
+			version: 9,
+			calls: [][]byte{{
+				0x00, 0x56, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x5e, 0x16, 0x01, 0x21, 0x00,
+				0x01, 0x01, 0x11, 0x01, 0x01, 0x0d, 0x00, 0x00, 0x00, 0x01, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x11, 0x53, 0x45, 0x4c, 0x45, 0x43, 0x54, 0x20, 0x2a, 0x20, 0x46,
+				0x52, 0x4f, 0x4d, 0x20, 0x65, 0x6d, 0x70, 0x01, 0x01, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			}},
+			want: &Query{Function: packet.OALL8, Query: "SELECT * FROM emp"},
+		},
+		{
+			name:    "OALL8 12.2 EXT1 layout with SQL signature and SQL id",
+			version: 9,
+			calls: [][]byte{{
+				0x00, 0x98, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x5e, 0x16, 0x01, 0x29, 0x00,
+				0x01, 0x01, 0x22, 0x01, 0x01, 0x0d, 0x00, 0x00, 0x00, 0x01, 0x64, 0x00, 0x01, 0x01, 0x01, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x01, 0x01, 0x10, 0x01, 0x01, 0x0d, 0x01, 0x22, 0x53, 0x45, 0x4c, 0x45, 0x43, 0x54, 0x20,
+				0x2a, 0x20, 0x46, 0x52, 0x4f, 0x4d, 0x20, 0x65, 0x6d, 0x70, 0x20, 0x57, 0x48, 0x45, 0x52, 0x45,
+				0x20, 0x65, 0x6d, 0x70, 0x6e, 0x6f, 0x20, 0x3d, 0x20, 0x3a, 0x31, 0x01, 0x01, 0x01, 0x01, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xa0, 0xa1, 0xa2, 0xa3, 0xa4, 0xa5,
+				0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xab, 0xac, 0xad, 0xae, 0xaf, 0x35, 0x61, 0x37, 0x62, 0x7a, 0x38,
+				0x63, 0x32, 0x78, 0x71, 0x31, 0x6d, 0x6e, 0x02, 0x03, 0x00, 0x00, 0x01, 0x16, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x07, 0x02, 0xc1, 0x08,
+			}},
+			want:   &Query{Function: packet.OALL8, Query: "SELECT * FROM emp WHERE empno = :1"},
+			values: []string{"7"},
+		},
+		{
+			name:    "OALL8 18.1 EXT1 layout with SQL id and chunk ids",
+			version: 11,
+			calls: [][]byte{{
+				0x00, 0x9f, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x5e, 0x16, 0x01, 0x29, 0x00,
+				0x01, 0x01, 0x22, 0x01, 0x01, 0x0d, 0x00, 0x00, 0x00, 0x01, 0x64, 0x00, 0x01, 0x01, 0x01, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x01, 0x01, 0x10, 0x01, 0x01, 0x0d, 0x01, 0x01, 0x01, 0x02, 0x22, 0x53, 0x45, 0x4c, 0x45,
+				0x43, 0x54, 0x20, 0x2a, 0x20, 0x46, 0x52, 0x4f, 0x4d, 0x20, 0x65, 0x6d, 0x70, 0x20, 0x57, 0x48,
+				0x45, 0x52, 0x45, 0x20, 0x65, 0x6d, 0x70, 0x6e, 0x6f, 0x20, 0x3d, 0x20, 0x3a, 0x31, 0x01, 0x01,
+				0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xa0, 0xa1, 0xa2,
+				0xa3, 0xa4, 0xa5, 0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xab, 0xac, 0xad, 0xae, 0xaf, 0x35, 0x61, 0x37,
+				0x62, 0x7a, 0x38, 0x63, 0x32, 0x78, 0x71, 0x31, 0x6d, 0x6e, 0x01, 0x07, 0x01, 0x09, 0x02, 0x03,
+				0x00, 0x00, 0x01, 0x16, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x07, 0x02, 0xc1, 0x08,
+			}},
+			want:   &Query{Function: packet.OALL8, Query: "SELECT * FROM emp WHERE empno = :1"},
+			values: []string{"7"},
+		},
+		{
+			name:    "OALL8 19c layout",
+			version: 12,
+			calls: [][]byte{{
+				0x00, 0x58, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x5e, 0x16, 0x01, 0x21, 0x00,
+				0x01, 0x01, 0x11, 0x01, 0x01, 0x0d, 0x00, 0x00, 0x00, 0x01, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x11, 0x53, 0x45, 0x4c, 0x45, 0x43, 0x54, 0x20, 0x2a,
+				0x20, 0x46, 0x52, 0x4f, 0x4d, 0x20, 0x65, 0x6d, 0x70, 0x01, 0x01, 0x01, 0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			}},
+			want: &Query{Function: packet.OALL8, Query: "SELECT * FROM emp"},
+		},
+		{
+			name: "OALL8 18.1 layout detected",
+			calls: [][]byte{{
+				0x00, 0x60, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x5e, 0x16, 0x01, 0x21, 0x00,
+				0x01, 0x01, 0x18, 0x01, 0x01, 0x0d, 0x00, 0x00, 0x00, 0x01, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x01, 0x03,
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x18, 0x49, 0x4e, 0x53, 0x45, 0x52, 0x54, 0x20,
+				0x49, 0x4e, 0x54, 0x4f, 0x20, 0x74, 0x20, 0x56, 0x41, 0x4c, 0x55, 0x45, 0x53, 0x20, 0x28, 0x31,
+				0x29, 0x01, 0x01, 0x01, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			}},
+			want: &Query{Function: packet.OALL8, Query: "INSERT INTO t VALUES (1)", RowCounts: true},
+		},
+		{
+			name:    "OALL8 re-execution with the detected layout",
+			cursors: map[uint32]*Query{5: {Query: "INSERT INTO t VALUES (1)"}},
+			calls: [][]byte{oall8Insert122, {
+				0x00, 0x40, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x5e, 0x16, 0x01, 0x21, 0x01,
+				0x05, 0x01, 0x00, 0x01, 0x01, 0x0d, 0x00, 0x00, 0x00, 0x01, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x01, 0x03,
+				0x01, 0x01, 0x01, 0x01, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			}},
+			want: &Query{Function: packet.OALL8, Query: "INSERT INTO t VALUES (1)", CursorId: 5, RowCounts: true, Reexecuted: true},
+		},
+		{
+			name: "OALL7",
+			calls: [][]byte{{
+				0x00, 0x2e, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x47, 0x17, 0x01, 0x61, 0x01,
+				0x05, 0x01, 0x01, 0x11, 0x00, 0x00, 0x00, 0x00, 0x01, 0x01, 0x01, 0x18, 0x11, 0x53, 0x45, 0x4c,
+				0x45, 0x43, 0x54, 0x20, 0x2a, 0x20, 0x46, 0x52, 0x4f, 0x4d, 0x20, 0x65, 0x6d, 0x70,
+			}},
+			want: &Query{Function: packet.OALL7, Query: "SELECT * FROM emp", CursorId: 5},
+		},
+		{
+			name: "OALL7 with a bind",
+			calls: [][]byte{{
+				0x00, 0x51, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x47, 0x17, 0x01, 0x69, 0x01,
+				0x05, 0x01, 0x01, 0x22, 0x01, 0x01, 0x01, 0x00, 0x00, 0x01, 0x01, 0x01, 0x18, 0x22, 0x53, 0x45,
+				0x4c, 0x45, 0x43, 0x54, 0x20, 0x2a, 0x20, 0x46, 0x52, 0x4f, 0x4d, 0x20, 0x65, 0x6d, 0x70, 0x20,
+				0x57, 0x48, 0x45, 0x52, 0x45, 0x20, 0x65, 0x6d, 0x70, 0x6e, 0x6f, 0x20, 0x3d, 0x20, 0x3a, 0x31,
+				0x02, 0x03, 0x00, 0x00, 0x01, 0x16, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x07, 0x02, 0xc1,
+				0x08,
+			}},
+			want:   &Query{Function: packet.OALL7, Query: "SELECT * FROM emp WHERE empno = :1", CursorId: 5},
+			values: []string{"7"},
+		},
+		{
+			name: "OSQL7",
+			calls: [][]byte{{
+				0x00, 0x24, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x4a, 0x18, 0x01, 0x06, 0x01,
+				0x01, 0x11, 0x11, 0x53, 0x45, 0x4c, 0x45, 0x43, 0x54, 0x20, 0x2a, 0x20, 0x46, 0x52, 0x4f, 0x4d,
+				0x20, 0x65, 0x6d, 0x70,
+			}},
+			want: &Query{Function: packet.OSQL7, Query: "SELECT * FROM emp", CursorId: 6},
+		},
+		{
+			name: "OPARSE",
+			calls: [][]byte{{
+				0x00, 0x24, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x22, 0x1a, 0x01, 0x05, 0x01,
+				0x01, 0x11, 0x11, 0x53, 0x45, 0x4c, 0x45, 0x43, 0x54, 0x20, 0x2a, 0x20, 0x46, 0x52, 0x4f, 0x4d,
+				0x20, 0x65, 0x6d, 0x70,
+			}},
+			want: &Query{Function: packet.OPARSE, Query: "SELECT * FROM emp", CursorId: 5},
+		},
+		{
+			name: "OEXEC of the statement parsed by OPARSE",
+			calls: [][]byte{{
+				0x00, 0x24, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x22, 0x1a, 0x01, 0x05, 0x01,
+				0x01, 0x11, 0x11, 0x53, 0x45, 0x4c, 0x45, 0x43, 0x54, 0x20, 0x2a, 0x20, 0x46, 0x52, 0x4f, 0x4d,
+				0x20, 0x65, 0x6d, 0x70,
+			}, {
+				0x00, 0x11, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x04, 0x19, 0x01, 0x05, 0x01,
+				0x01,
+			}},
+			want: &Query{Function: packet.OEXEC, Query: "SELECT * FROM emp", CursorId: 5, Reexecuted: true},
+		},
+		{
+			name:    "OEXEC",
+			cursors: map[uint32]*Query{5: {Query: "SELECT * FROM emp"}},
+			calls: [][]byte{{
+				0x00, 0x11, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x04, 0x19, 0x01, 0x05, 0x01,
+				0x01,
+			}},
+			want: &Query{Function: packet.OEXEC, Query: "SELECT * FROM emp", CursorId: 5, Reexecuted: true},
+		},
+		{
+			name: "OEXEC with new bind values",
+			cursors: map[uint32]*Query{5: {
+				Query:  "SELECT * FROM emp WHERE empno = :1",
+				Params: []*ParameterInfo{{DataType: NUMBER, Flag: 3, MaxLen: 22}},
+			}},
+			calls: [][]byte{{
+				0x00, 0x15, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x04, 0x19, 0x01, 0x05, 0x01,
+				0x01, 0x07, 0x02, 0xc1, 0x08,
+			}},
+			want:   &Query{Function: packet.OEXEC, Query: "SELECT * FROM emp WHERE empno = :1", CursorId: 5, Reexecuted: true},
+			values: []string{"7"},
+		},
+		{
+			name: "OEXEC on a cursor opened before the trace",
+			calls: [][]byte{{
+				0x00, 0x11, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x04, 0x19, 0x01, 0x05, 0x01,
+				0x01,
+			}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Parser{}
+			s := newSession(1, &trc.Packet{})
+			if tt.version > 0 {
+				s.ClientCompileCaps = []byte{0, 0, 0, 0, 0, 0, 0, tt.version}
+			}
+			for id, q := range tt.cursors {
+				s.cursors[id] = q
+			}
+			for _, b := range tt.calls {
+				s.pending = nil
+				p.parseQuery(s, &trc.Packet{Payload: b})
+			}
+			got := s.pending
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("parseQuery() = %q, want no query", got.Query)
+				}
+				return
+			}
+			if got == nil {
+				t.Errorf("parseQuery() = nil, want %q", tt.want.Query)
+				return
+			}
+			if got.Function != tt.want.Function || got.Query != tt.want.Query || got.CursorId != tt.want.CursorId {
+				t.Errorf("parseQuery() = %v %q on cursor %d, want %v %q on cursor %d",
+					got.Function, got.Query, got.CursorId, tt.want.Function, tt.want.Query, tt.want.CursorId)
+			}
+			if got.RowCounts != tt.want.RowCounts || got.Reexecuted != tt.want.Reexecuted {
+				t.Errorf("RowCounts = %v, Reexecuted = %v, want %v, %v", got.RowCounts, got.Reexecuted, tt.want.RowCounts, tt.want.Reexecuted)
+			}
+			values := []string{}
+			for _, p := range got.Params {
+				values = append(values, p.String())
+			}
+			if len(values) != len(tt.values) || strings.Join(values, ", ") != strings.Join(tt.values, ", ") {
+				t.Errorf("Binds = %v, want %v", values, tt.values)
+			}
+		})
+	}
+}
+
+func Test_isStatement(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"SELECT * FROM dual", true},
+		{"  (select 1 from dual) union (select 2 from dual)", true},
+		{"/* hint */ INSERT INTO t VALUES (1)", true},
+		{"-- comment\nbegin null; end;", true},
+		{"create table t (n number)", true},
+		{"SYS", false},
+		{"\x00\x11SELECT", false},
+		{"/* unterminated", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := isStatement([]byte(tt.s)); got != tt.want {
+				t.Errorf("isStatement(%q) = %v, want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
@@ -7,95 +7,31 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/simulot/oracle_trc/packet"
 )
 
+// GetInt64 reads an integer, when compress is set, the size is given by the first byte
+//
+// Deprecated: use packet.GetInt64
 func GetInt64(r *bytes.Buffer, size int, compress bool, bigEndian bool) (int64, error) {
-	var ret int64
-
-	negFlag := false
-	if compress {
-		b, err := r.ReadByte()
-		if err != nil {
-			return 0, err
-		}
-		if (b & 0x80) != 0 {
-			negFlag = true
-		}
-		size = int(b & 0x7F)
-		bigEndian = true
-	}
-	if size == 0 {
-		return 0, nil
-	}
-	buff := r.Next(size)
-	temp := make([]byte, 8)
-	if bigEndian {
-		copy(temp[8-size:], buff)
-		ret = int64(binary.BigEndian.Uint64(temp))
-	} else {
-		copy(temp[:size], buff)
-		ret = int64(binary.LittleEndian.Uint64(temp))
-	}
-	if negFlag {
-		ret = ret * -1
-	}
-	return ret, nil
+	return packet.GetInt64(r, size, compress, bigEndian)
 }
 
+// GetInt reads a 32 bits integer
+//
+// Deprecated: use packet.GetInt
 func GetInt(r *bytes.Buffer, size int, compress bool, bigEndian bool) (int32, error) {
-	temp, err := GetInt64(r, size, compress, bigEndian)
-	if err != nil {
-		return 0, err
-	}
-	return int32(temp), nil
+	return packet.GetInt(r, size, compress, bigEndian)
 }
 
+// GetUInt reads a 32 bits unsigned integer
+//
+// Deprecated: use packet.GetUInt
 func GetUInt(r *bytes.Buffer, size int, compress bool, bigEndian bool) (uint32, error) {
-	i, err := GetInt(r, size, compress, bigEndian)
-	return uint32(i), err
-}
-
-func readBytes(buff *bytes.Buffer) ([]byte, error) {
-	out := make([]byte, 0, 40)
-	var l, b byte
-	var err error
-	hasChunks := false
-
-	l, err = buff.ReadByte()
-	if err != nil {
-		return nil, err
-	}
-	if l == 0xFE {
-		// Marker for buffer bigger than 0x40
-		l, err = buff.ReadByte()
-		if err != nil {
-			return nil, err
-		}
-		hasChunks = true
-	}
-
-extern:
-	for l > 0 {
-		for l > 0 {
-			l--
-			b, err = buff.ReadByte()
-			if err != nil {
-				break extern
-			}
-			out = append(out, b)
-		}
-		if hasChunks {
-			l, err = buff.ReadByte()
-			if err != nil {
-				break extern
-			}
-		}
-	}
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+	return packet.GetUInt(r, size, compress, bigEndian)
 }
 
 // Information found on GO-ORA project by Samy Sultan
@@ -110,6 +46,9 @@ const (
 	RetVal ParameterDirection = 9
 )
 
+// bindOutput is the bit of the bind metadata flag set on binds receiving a value, the one sent by the client is ignored
+const bindOutput uint8 = 0x80
+
 //go:generate stringer -type=OracleType
 
 const (
@@ -161,6 +100,9 @@ const (
 	TimeStampeLTZ    OracleType = 232
 )
 
+// TypeTable gives the representation of each data type, as negotiated with TTIDTY
+type TypeTable map[OracleType]packet.TypeRepresentation
+
 type ParameterType int
 
 const (
@@ -174,6 +116,7 @@ type ParameterInfo struct {
 	IsNull               bool
 	AllowNull            bool
 	ColAlias             string
+	TypeName             string // Name of object types
 	DataType             OracleType
 	IsXmlType            bool
 	Flag                 uint8
@@ -188,16 +131,33 @@ type ParameterInfo struct {
 	CharsetID            uint32
 	CharsetForm          uint8
 	Value                []byte
+	Elements             [][]byte   // Values of PL/SQL array binds, Value is nil
+	OutValues            [][]byte   // Values sent back by the server for output binds, one per row for RETURNING INTO
+	WireType             OracleType // Type of the value on the wire, after the negotiated conversion
+	Lob                  *Lob       // LOB of the locator, for CLOB and BLOB values
 	getDataFromServer    bool
 }
 
+// valueType gives the type used to decode the value
+func (p ParameterInfo) valueType() OracleType {
+	if p.WireType != 0 {
+		return p.WireType
+	}
+	return p.DataType
+}
+
 func (p ParameterInfo) String() string {
 	if p.IsNull {
 		return "(null)"
 	}
-	switch p.DataType {
-	case CHAR:
+	if p.Elements != nil {
+		return p.elementsString()
+	}
+	switch p.valueType() {
+	case CHAR, NCHAR, VARCHAR, LONG:
 		return string(p.Value)
+	case RAW, LongRaw:
+		return fmt.Sprintf("%X", p.Value)
 	case DATE, TimeStamp, TimeStampDTY, TimeStampeLTZ, TimeStampLTZ_DTY, TimeStampTZ, TimeStampTZ_DTY:
 		d, err := DecodeDate(p.Value)
 		if err != nil {
@@ -206,11 +166,105 @@ func (p ParameterInfo) String() string {
 		return d.Format(time.RFC3339)
 	case NUMBER:
 		return strconv.FormatFloat(DecodeDouble(p.Value), 'g', -1, 64)
+	case OCIClobLocator, OCIBlobLocator:
+		if p.Lob != nil {
+			return p.Lob.String()
+		}
+		return "(" + p.DataType.String() + ")"
 	default:
 		return "(" + p.DataType.String() + ")"
 	}
 }
 
+// Number of shown elements of PL/SQL arrays
+const elementsPreview = 10
+
+// elementsString shows the first elements of a PL/SQL array bind
+func (p ParameterInfo) elementsString() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%d element(s) [", len(p.Elements)))
+	for i, v := range p.Elements {
+		if i == elementsPreview {
+			sb.WriteString(", ...")
+			break
+		}
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		e := p
+		e.Elements, e.Value, e.IsNull = nil, v, v == nil
+		sb.WriteString(e.quoted())
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// quoted gives the value as a literal, text values are quoted
+func (p ParameterInfo) quoted() string {
+	if p.IsNull || p.Elements != nil {
+		return p.String()
+	}
+	switch p.valueType() {
+	case CHAR, NCHAR, VARCHAR, LONG:
+		return "'" + strings.Replace(p.String(), "'", "''", -1) + "'"
+	}
+	return p.String()
+}
+
+// readBindValue reads the value of a bind.
+// PL/SQL array binds are sent as the number of elements followed by the elements.
+func readBindValue(buff *bytes.Buffer, p *ParameterInfo) error {
+	var err error
+	p.Elements = nil
+	if p.MaxNoOfArrayElements == 0 {
+		p.Value, err = packet.ReadBytes(buff)
+		return err
+	}
+	p.Value = nil
+	var n uint32
+	n, err = packet.GetUInt(buff, 4, true, true)
+	if err != nil {
+		return err
+	}
+	p.Elements = [][]byte{}
+	for i := 0; i < int(n); i++ {
+		var v []byte
+		v, err = packet.ReadBytes(buff)
+		if err != nil {
+			return err
+		}
+		p.Elements = append(p.Elements, v)
+	}
+	return nil
+}
+
+// columnInfo gives the description of a result set's column
+func columnInfo(c packet.Column) *ParameterInfo {
+	p := &ParameterInfo{
+		Name:                 c.Name,
+		TypeName:             c.TypeName,
+		AllowNull:            c.Nullable,
+		DataType:             OracleType(c.DataType),
+		Flag:                 c.Flag,
+		Precision:            c.Precision,
+		Scale:                uint8(c.Scale),
+		MaxLen:               c.MaxLen,
+		MaxCharLen:           c.MaxCharLen,
+		MaxNoOfArrayElements: c.MaxArrayLen,
+		ContFlag:             uint32(c.ContFlag),
+		ToID:                 c.ToID,
+		Version:              uint32(c.Version),
+		CharsetID:            uint32(c.CharsetID),
+		CharsetForm:          c.CharsetForm,
+		getDataFromServer:    true,
+	}
+	if c.Scale == -127 {
+		// FLOAT number
+		p.Scale = 0xFF
+	}
+	return p
+}
+
 func GetParamInfo(buff *bytes.Buffer) (*ParameterInfo, error) {
 	p := &ParameterInfo{}
 	var err error
@@ -236,16 +290,16 @@ func GetParamInfo(buff *bytes.Buffer) (*ParameterInfo, error) {
 		return nil, err
 	}
 
-	p.MaxLen, err = GetUInt(buff, 4, true, true)
+	p.MaxLen, err = packet.GetUInt(buff, 4, true, true)
 	if err != nil {
 		return nil, err
 	}
-	p.MaxNoOfArrayElements, err = GetUInt(buff, 4, true, true)
+	p.MaxNoOfArrayElements, err = packet.GetUInt(buff, 4, true, true)
 	if err != nil {
 		return nil, err
 	}
 
-	p.ContFlag, err = GetUInt(buff, 4, true, true)
+	p.ContFlag, err = packet.GetUInt(buff, 4, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -257,11 +311,11 @@ func GetParamInfo(buff *bytes.Buffer) (*ParameterInfo, error) {
 	}
 	if b > 0 {
 		var blen uint32
-		blen, err = GetUInt(buff, 4, true, true)
+		blen, err = packet.GetUInt(buff, 4, true, true)
 		if err != nil {
 			return nil, err
 		}
-		p.ToID, err = readBytes(buff)
+		p.ToID, err = packet.ReadBytes(buff)
 		if err != nil {
 			return nil, err
 		}
@@ -272,12 +326,12 @@ func GetParamInfo(buff *bytes.Buffer) (*ParameterInfo, error) {
 		}
 	}
 
-	p.Version, err = GetUInt(buff, 2, true, true)
+	p.Version, err = packet.GetUInt(buff, 2, true, true)
 	if err != nil {
 		return nil, err
 	}
 
-	p.CharsetID, err = GetUInt(buff, 2, true, true)
+	p.CharsetID, err = packet.GetUInt(buff, 2, true, true)
 	if err != nil {
 		return nil, err
 	}
@@ -287,7 +341,7 @@ func GetParamInfo(buff *bytes.Buffer) (*ParameterInfo, error) {
 		return nil, err
 	}
 
-	p.MaxCharLen, err = GetUInt(buff, 2, true, true)
+	p.MaxCharLen, err = packet.GetUInt(buff, 2, true, true)
 	if err != nil {
 		return nil, err
 	}
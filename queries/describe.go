@@ -0,0 +1,129 @@
+package queries
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/simulot/oracle_trc/packet"
+)
+
+// Describe holds the columns of a query's result set, as described by the server when the cursor is parsed
+type Describe struct {
+	MaxRowSize uint32
+	Columns    []*ParameterInfo
+}
+
+// newDescribe converts the describe message
+func newDescribe(m packet.Describe) *Describe {
+	d := &Describe{
+		MaxRowSize: m.MaxRowSize,
+		Columns:    make([]*ParameterInfo, len(m.Columns)),
+	}
+	for i, c := range m.Columns {
+		d.Columns[i] = columnInfo(c)
+	}
+	return d
+}
+
+// packetColumns gives what the decoding of rows needs
+func (d *Describe) packetColumns() []packet.Column {
+	if d == nil {
+		return nil
+	}
+	cols := make([]packet.Column, len(d.Columns))
+	for i, c := range d.Columns {
+		cols[i] = packet.Column{DataType: uint8(c.DataType)}
+	}
+	return cols
+}
+
+// String gives one line per column: name, SQL type and nullability
+func (d Describe) String() string {
+	width := 0
+	for _, c := range d.Columns {
+		if len(c.Name) > width {
+			width = len(c.Name)
+		}
+	}
+	sb := strings.Builder{}
+	for _, c := range d.Columns {
+		sb.WriteString("  ")
+		sb.WriteString(c.Name)
+		sb.WriteString(strings.Repeat(" ", width-len(c.Name)+1))
+		sb.WriteString(c.sqlType())
+		if !c.AllowNull {
+			sb.WriteString(" NOT NULL")
+		}
+		writeEol(&sb)
+	}
+	return sb.String()
+}
+
+// sqlType gives the column type as written in SQL
+func (p ParameterInfo) sqlType() string {
+	national := ""
+	if p.CharsetForm == 2 {
+		national = "N"
+	}
+	switch p.DataType {
+	case NCHAR:
+		// Type 1 is VARCHAR2, the length is in characters for national character sets
+		if national != "" {
+			return fmt.Sprintf("NVARCHAR2(%d)", p.MaxCharLen)
+		}
+		return fmt.Sprintf("VARCHAR2(%d)", p.MaxLen)
+	case CHAR:
+		if national != "" {
+			return fmt.Sprintf("NCHAR(%d)", p.MaxCharLen)
+		}
+		return fmt.Sprintf("CHAR(%d)", p.MaxLen)
+	case NUMBER:
+		switch {
+		case p.Scale == 0xFF && p.Precision == 0:
+			return "NUMBER"
+		case p.Scale == 0xFF:
+			return fmt.Sprintf("FLOAT(%d)", p.Precision)
+		case p.Precision == 0:
+			return "NUMBER"
+		case p.Scale == 0:
+			return fmt.Sprintf("NUMBER(%d)", p.Precision)
+		}
+		return fmt.Sprintf("NUMBER(%d,%d)", p.Precision, p.Scale)
+	case DATE:
+		return "DATE"
+	case LONG:
+		return "LONG"
+	case RAW:
+		return fmt.Sprintf("RAW(%d)", p.MaxLen)
+	case LongRaw:
+		return "LONG RAW"
+	case ROWID:
+		return "ROWID"
+	case UROWID:
+		return "UROWID"
+	case IBFloat:
+		return "BINARY_FLOAT"
+	case IBDouble:
+		return "BINARY_DOUBLE"
+	case OCIClobLocator:
+		return national + "CLOB"
+	case OCIBlobLocator:
+		return "BLOB"
+	case OCIFileLocator:
+		return "BFILE"
+	case TimeStampDTY:
+		return fmt.Sprintf("TIMESTAMP(%d)", p.Scale)
+	case TimeStampTZ_DTY:
+		return fmt.Sprintf("TIMESTAMP(%d) WITH TIME ZONE", p.Scale)
+	case TimeStampLTZ_DTY:
+		return fmt.Sprintf("TIMESTAMP(%d) WITH LOCAL TIME ZONE", p.Scale)
+	case IntervalYM_DTY:
+		return fmt.Sprintf("INTERVAL YEAR(%d) TO MONTH", p.Precision)
+	case IntervalDS_DTY:
+		return fmt.Sprintf("INTERVAL DAY(%d) TO SECOND(%d)", p.Precision, p.Scale)
+	}
+	if p.TypeName != "" {
+		return p.TypeName
+	}
+	return p.DataType.String()
+}
@@ -0,0 +1,128 @@
+package queries
+
+import "testing"
+
+func TestParameterInfo_sqlType(t *testing.T) {
+	tests := []struct {
+		p    ParameterInfo
+		want string
+	}{
+		{ParameterInfo{DataType: NCHAR, MaxLen: 30, CharsetForm: 1}, "VARCHAR2(30)"},
+		{ParameterInfo{DataType: NCHAR, MaxLen: 60, MaxCharLen: 30, CharsetForm: 2}, "NVARCHAR2(30)"},
+		{ParameterInfo{DataType: CHAR, MaxLen: 1, CharsetForm: 1}, "CHAR(1)"},
+		{ParameterInfo{DataType: NUMBER}, "NUMBER"},
+		{ParameterInfo{DataType: NUMBER, Precision: 10, Scale: 2}, "NUMBER(10,2)"},
+		{ParameterInfo{DataType: NUMBER, Precision: 126, Scale: 0xFF}, "FLOAT(126)"},
+		{ParameterInfo{DataType: OCIClobLocator, CharsetForm: 2}, "NCLOB"},
+		{ParameterInfo{DataType: TimeStampDTY, Scale: 6}, "TIMESTAMP(6)"},
+		{ParameterInfo{DataType: OCIRef, TypeName: "ADDRESS_T"}, "ADDRESS_T"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.p.sqlType(); got != tt.want {
+				t.Errorf("sqlType() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_describeResponse(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=109.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=109
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 6D 00 00 06 00 00 00  |.m......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 03 5E 16 01 21 00  |...^..!.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 31 01 01 0D 00 00  |..1.....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 64 00 00 00 00 00  |..d.....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00 31 53 45  |.....1SE|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 4C 45 43 54 20 65 6D 70  |LECT.emp|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 6E 6F 2C 20 65 6E 61 6D  |no,.enam|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 65 2C 20 68 69 72 65 64  |e,.hired|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 61 74 65 2C 20 73 61 6C  |ate,.sal|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 2C 20 6E 6F 74 65 20 46  |,.note.F|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 52 4F 4D 20 65 6D 70 01  |ROM.emp.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00           |.....   |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=201
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 C9 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 10 00 01 78 01 05  |.....x..|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 02 00 04 00 01 16 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 05 01 05 05 45 4D 50 4E  |....EMPN|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 4F 00 00 00 00 01 00 00  |O.......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 0A 00 00 00 00 02  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 03 69 01 01 0A 00 01 05  |.i......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 05 05 45 4E 41 4D 45  |...ENAME|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 0C 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 07 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 01 08 01 08 08 48  |.......H|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 49 52 45 44 41 54 45 00  |IREDATE.|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 02 00 07 01 02  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 16 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 01 03 01 03 03 53  |.......S|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 41 4C 00 00 00 00 01 00  |AL......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 01 28 00 00 00 00  |...(....|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 02 07 D0 02 01 14 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 04 01 04 04 4E 4F 54 45  |....NOTE|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 04 00 00 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 09 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00                       |.       |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=191, tot=201, rc=0
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	if len(got) != 1 || got[0].Describe == nil {
+		t.Errorf("Number of queries = %d, want 1 with its columns described", len(got))
+		return
+	}
+	want := []ParameterInfo{
+		{Name: "EMPNO", DataType: NUMBER, Precision: 4, MaxLen: 22},
+		{Name: "ENAME", DataType: NCHAR, MaxLen: 10, MaxCharLen: 10, CharsetID: 873, CharsetForm: 1, AllowNull: true},
+		{Name: "HIREDATE", DataType: DATE, MaxLen: 7, AllowNull: true},
+		{Name: "SAL", DataType: NUMBER, Precision: 7, Scale: 2, MaxLen: 22, AllowNull: true},
+		{Name: "NOTE", DataType: NCHAR, MaxLen: 40, MaxCharLen: 20, CharsetID: 2000, CharsetForm: 2, AllowNull: true},
+	}
+	cols := got[0].Describe.Columns
+	if len(cols) != len(want) {
+		t.Errorf("Columns = %d, want %d", len(cols), len(want))
+		return
+	}
+	for i, w := range want {
+		c := cols[i]
+		if c.Name != w.Name || c.DataType != w.DataType || c.Precision != w.Precision || c.Scale != w.Scale ||
+			c.MaxLen != w.MaxLen || c.MaxCharLen != w.MaxCharLen || c.CharsetID != w.CharsetID ||
+			c.CharsetForm != w.CharsetForm || c.AllowNull != w.AllowNull {
+			t.Errorf("Column %d = %+v, want %+v", i, *c, w)
+		}
+	}
+
+	// As printed by -describe
+	wantDescribe := "" +
+		"  EMPNO    NUMBER(4) NOT NULL\n" +
+		"  ENAME    VARCHAR2(10)\n" +
+		"  HIREDATE DATE\n" +
+		"  SAL      NUMBER(7,2)\n" +
+		"  NOTE     NVARCHAR2(20)\n"
+	if d := got[0].Describe.String(); d != wantDescribe {
+		t.Errorf("Describe = \n%s, want \n%s", d, wantDescribe)
+	}
+	if q := got[0].Query; q != "SELECT empno, ename, hiredate, sal, note FROM emp" {
+		t.Errorf("Query = %s", q)
+	}
+}
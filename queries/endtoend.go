@@ -0,0 +1,53 @@
+package queries
+
+import (
+	"strings"
+
+	"github.com/simulot/oracle_trc/packet"
+)
+
+// EndToEnd holds the end-to-end attributes set by the client, they tag the calls of the session
+type EndToEnd struct {
+	Module     string // DBMS_APPLICATION_INFO module
+	Action     string // DBMS_APPLICATION_INFO action
+	ClientId   string // Client identifier
+	ClientInfo string // DBMS_APPLICATION_INFO client info
+}
+
+// String lists the attributes set, an empty string when there is none
+func (e EndToEnd) String() string {
+	l := []string{}
+	for _, a := range []struct{ name, value string }{
+		{"module", e.Module},
+		{"action", e.Action},
+		{"client id", e.ClientId},
+		{"client info", e.ClientInfo},
+	} {
+		if a.value != "" {
+			l = append(l, a.name+" "+a.value)
+		}
+	}
+	return strings.Join(l, ", ")
+}
+
+// apply updates the attributes set by the piggybacks
+func (e *EndToEnd) apply(piggybacks []packet.TTCMessage) {
+	for _, m := range piggybacks {
+		m, ok := m.(packet.EndToEnd)
+		if !ok {
+			continue
+		}
+		if m.Flags&packet.EndToEndModule != 0 {
+			e.Module = m.Module
+		}
+		if m.Flags&packet.EndToEndAction != 0 {
+			e.Action = m.Action
+		}
+		if m.Flags&packet.EndToEndClientId != 0 {
+			e.ClientId = m.ClientId
+		}
+		if m.Flags&packet.EndToEndClientInfo != 0 {
+			e.ClientInfo = m.ClientInfo
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package queries
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_endToEnd(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: tot=0, plen=200.
+(5236) [22-OCT-2020 12:44:14:751] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:751] nttfpwr: socket 1288 had bytes written=200
+(5236) [22-OCT-2020 12:44:14:751] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 00 C8 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 00 00 11 87 15 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 18 00 00 01 01 06 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 09 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 00 00 00 00 00 06 4F 52  |......OR|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 44 45 52 53 09 4E 45 57  |DERS.NEW|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 5F 4F 52 44 45 52 03 5E  |_ORDER.^|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 16 02 80 69 00 01 01 AE  |...i....|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 01 01 0D 01 01 00 01 64  |.......d|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 00 01 01 01 00 01 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 01 01 00 00 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 00 00 00 3A 53 45 4C 45  |...:SELE|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 43 54 20 70 61 72 61 6D  |CT.param|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 5F 76 61 6C 75 65 20 46  |_value.F|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 52 4F 4D 20 65 66 6C 6F  |ROM.eflo|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 77 5F 70 61 72 61 6D 73  |w_params|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 20 57 48 45 52 45 20 70  |.WHERE.p|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 61 72 61 6D 5F 6E 61 6D  |aram_nam|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 65 20 3D 20 3A 31 01 01  |e.=.:1..|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 00 00 00 00 00 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 00 00 00 00 00 60 00 00  |.....` + "`" + `..|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 00 01 66 00 01 10 00 00  |..f.....|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 02 03 69 01 00 07 11 4C  |..i....L|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 49 43 45 58 50 49 52 41  |ICEXPIRA|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: 54 49 4F 4E 44 41 54 45  |TIONDATE|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=116
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 74 00 00 06 00 00 00  |.t......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 10 00 01 3B 01 03  |.....;..|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 02 00 0A 00 01 16 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 02 01 02 02 49 44 00 00  |....ID..|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 01 00 00 00 01 1E  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 1E 00 01 04 01 04 04  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 4E 41 4D 45 00 00 00 00  |NAME....|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 0C 00 00 00 01 07 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 01 00 00 01 07  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 07 07 43 52 45 41 54  |...CREAT|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 45 44 00 00 00 00 00 00  |ED......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 06 01 01 03  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 01 00              |....    |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=106, tot=116, rc=0
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: socket 1288 had bytes read=117
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 75 00 00 06 00 00 00  |.u......|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 00 00 07 02 C1 2B  |.......+|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 05 53 43 4F 54 54 07 78  |.SCOTT.x|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 78 0A 16 0D 2D 0F 15 01  |x...-...|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 02 05 07 02 C1 08 07 78  |.......x|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 78 0A 16 0D 2D 0F 07 02  |x...-...|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: C1 09 04 4B 49 4E 47 00  |...KING.|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 04 01 03 02 05 7B 00 00  |.....{..|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 01 02 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 00 00 00 00 00 19  |........|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 4F 52 41 2D 30 31 34 30  |ORA-0140|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 33 3A 20 6E 6F 20 64 61  |3:.no.da|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 74 61 20 66 6F 75 6E 64  |ta.found|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 0A 09 00 01 05           |.....   |
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: exit: oln=0, dln=107, tot=117, rc=0
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: tot=0, plen=156.
+(5236) [22-OCT-2020 12:44:14:754] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:754] nttfpwr: socket 1288 had bytes written=156
+(5236) [22-OCT-2020 12:44:14:754] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 9C 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 00 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:755] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:755] nttfprd: socket 1288 had bytes read=116
+(5236) [22-OCT-2020 12:44:14:755] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 74 00 00 06 00 00 00  |.t......|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 00 10 00 01 3B 01 03  |.....;..|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 01 02 00 0A 00 01 16 00  |........|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 00 00 00 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 02 01 02 02 49 44 00 00  |....ID..|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 00 01 00 00 00 01 1E  |........|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 00 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 01 1E 00 01 04 01 04 04  |........|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 4E 41 4D 45 00 00 00 00  |NAME....|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 0C 00 00 00 01 07 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 00 00 01 00 00 01 07  |........|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 01 07 07 43 52 45 41 54  |...CREAT|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 45 44 00 00 00 00 00 00  |ED......|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 00 00 00 06 01 01 03  |........|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 01 01 00              |....    |
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: exit: oln=0, dln=106, tot=116, rc=0
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:756] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:756] nttfprd: socket 1288 had bytes read=117
+(5236) [22-OCT-2020 12:44:14:756] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 00 75 00 00 06 00 00 00  |.u......|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 00 00 00 00 07 02 C1 2B  |.......+|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 05 53 43 4F 54 54 07 78  |.SCOTT.x|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 78 0A 16 0D 2D 0F 15 01  |x...-...|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 02 05 07 02 C1 08 07 78  |.......x|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 78 0A 16 0D 2D 0F 07 02  |x...-...|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: C1 09 04 4B 49 4E 47 00  |...KING.|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 04 01 03 02 05 7B 00 00  |.....{..|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 01 02 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 00 00 00 00 00 00 00 19  |........|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 4F 52 41 2D 30 31 34 30  |ORA-0140|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 33 3A 20 6E 6F 20 64 61  |3:.no.da|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 74 61 20 66 6F 75 6E 64  |ta.found|
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: 0A 09 00 01 05           |.....   |
+(5236) [22-OCT-2020 12:44:14:756] nsbasic_brc: exit: oln=0, dln=107, tot=117, rc=0
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: tot=0, plen=181.
+(5236) [22-OCT-2020 12:44:14:757] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:757] nttfpwr: socket 1288 had bytes written=181
+(5236) [22-OCT-2020 12:44:14:757] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 00 B5 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 00 00 11 87 15 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 10 00 00 00 00 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 00 00 00 03 5E 16 02 80  |....^...|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 69 00 01 01 AE 01 01 0D  |i.......|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 01 01 00 01 64 00 01 01  |....d...|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 01 00 01 00 01 01 01 00  |........|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 00 01 01 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 3A 53 45 4C 45 43 54 20  |:SELECT.|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 70 61 72 61 6D 5F 76 61  |param_va|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 6C 75 65 20 46 52 4F 4D  |lue.FROM|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 20 65 66 6C 6F 77 5F 70  |.eflow_p|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 61 72 61 6D 73 20 57 48  |arams.WH|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 45 52 45 20 70 61 72 61  |ERE.para|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 6D 5F 6E 61 6D 65 20 3D  |m_name.=|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 20 3A 31 01 01 00 00 00  |.:1.....|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 00 00 00 01 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 00 00 60 00 00 00 01 66  |..` + "`" + `....f|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 00 01 10 00 00 02 03 69  |.......i|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 01 00 07 11 4C 49 43 45  |....LICE|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 58 50 49 52 41 54 49 4F  |XPIRATIO|
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: 4E 44 41 54 45           |NDATE   |
+(5236) [22-OCT-2020 12:44:14:757] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:758] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:758] nttfprd: socket 1288 had bytes read=116
+(5236) [22-OCT-2020 12:44:14:758] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 00 74 00 00 06 00 00 00  |.t......|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 00 00 10 00 01 3B 01 03  |.....;..|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 01 02 00 0A 00 01 16 00  |........|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 00 00 00 00 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 02 01 02 02 49 44 00 00  |....ID..|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 00 00 01 00 00 00 01 1E  |........|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 00 00 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 01 1E 00 01 04 01 04 04  |........|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 4E 41 4D 45 00 00 00 00  |NAME....|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 0C 00 00 00 01 07 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 00 00 00 01 00 00 01 07  |........|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 01 07 07 43 52 45 41 54  |...CREAT|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 45 44 00 00 00 00 00 00  |ED......|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 00 00 00 00 06 01 01 03  |........|
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: 00 01 01 00              |....    |
+(5236) [22-OCT-2020 12:44:14:758] nsbasic_brc: exit: oln=0, dln=106, tot=116, rc=0
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:759] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:759] nttfprd: socket 1288 had bytes read=117
+(5236) [22-OCT-2020 12:44:14:759] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 00 75 00 00 06 00 00 00  |.u......|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 00 00 00 00 07 02 C1 2B  |.......+|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 05 53 43 4F 54 54 07 78  |.SCOTT.x|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 78 0A 16 0D 2D 0F 15 01  |x...-...|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 02 05 07 02 C1 08 07 78  |.......x|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 78 0A 16 0D 2D 0F 07 02  |x...-...|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: C1 09 04 4B 49 4E 47 00  |...KING.|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 04 01 03 02 05 7B 00 00  |.....{..|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 01 02 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 00 00 00 00 00 00 00 19  |........|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 4F 52 41 2D 30 31 34 30  |ORA-0140|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 33 3A 20 6E 6F 20 64 61  |3:.no.da|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 74 61 20 66 6F 75 6E 64  |ta.found|
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: 0A 09 00 01 05           |.....   |
+(5236) [22-OCT-2020 12:44:14:759] nsbasic_brc: exit: oln=0, dln=107, tot=117, rc=0
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	want := []EndToEnd{
+		{Module: "ORDERS", Action: "NEW_ORDER"},
+		{Module: "ORDERS", Action: "NEW_ORDER"},
+		{Module: "ORDERS"},
+	}
+	if len(got) != len(want) {
+		t.Errorf("Number of queries = %d, want %d", len(got), len(want))
+		return
+	}
+	for i, q := range got {
+		if q.EndToEnd != want[i] {
+			t.Errorf("Query #%d end-to-end attributes = %+v, want %+v", i+1, q.EndToEnd, want[i])
+		}
+	}
+	if s := got[1].String(); !strings.Contains(s, "\n  module ORDERS, action NEW_ORDER\nSELECT") {
+		t.Errorf("String() = %s", s)
+	}
+	if s := got[0].Session; s == nil || s.EndToEnd != want[2] {
+		t.Errorf("Session's end-to-end attributes = %+v, want %+v", s.EndToEnd, want[2])
+	}
+}
@@ -0,0 +1,103 @@
+package queries
+
+import (
+	"strings"
+
+	"github.com/simulot/oracle_trc/packet"
+)
+
+// fetch is an OFETCH call waiting for its response, tied to the query that opened the cursor
+type fetch struct {
+	q        *Query
+	response *Response
+}
+
+// startFetch ties the fetch call to the query still fetching on the cursor.
+// Fetches on cursors opened before the beginning of the trace are ignored.
+func (s *Session) startFetch(f packet.FetchCall) {
+	q := s.open[f.CursorId]
+	if q == nil {
+		return
+	}
+	s.fetch = &fetch{
+		q:        q,
+		response: &Response{},
+	}
+}
+
+// endFetch adds the rows of the fetch's response to the query.
+// The query is sent when the end of fetch or an error is reached.
+func (p *Parser) endFetch(s *Session) {
+	f := s.fetch
+	if f == nil {
+		return
+	}
+	s.fetch = nil
+	if len(f.response.Packets) == 0 {
+		return
+	}
+
+	q, r := f.q, f.response
+	r.decode(s.responseContext(packet.OFETCH), q.Describe)
+	q.Fetches++
+	if r.RowCount > q.RowsFetched {
+		// The server counts the rows fetched since the execution
+		q.RowsFetched = r.RowCount
+	}
+	q.Response.Packets = append(q.Response.Packets, r.Packets...)
+	q.Response.Rows = append(q.Response.Rows, r.Rows...)
+	q.Response.RowData = append(q.Response.RowData, r.RowData...)
+	q.Response.RowCount = q.RowsFetched
+	s.attachLobs(q)
+	if r.ErrorCode == 0 {
+		return
+	}
+	q.Response.ErrorCode = r.ErrorCode
+	q.Response.ErrorPos = r.ErrorPos
+	q.Response.ErrorMessage = r.ErrorMessage
+	if r.ErrorCode != errNoDataFound {
+		q.Err = r.Err()
+	}
+	p.endFetching(s, q.CursorId)
+}
+
+// endFetching sends the query fetching on the cursor, if any
+func (p *Parser) endFetching(s *Session, id uint32) {
+	q, ok := s.open[id]
+	if !ok {
+		return
+	}
+	delete(s.open, id)
+	p.release(s, q)
+}
+
+// fetching checks if the query's rows may be fetched by following OFETCH calls.
+// Clients without prefetch execute the query without the fetch option, all its rows come with OFETCH calls.
+func (q *Query) fetching() bool {
+	if q.CursorId == 0 || q.Response == nil || q.Err != nil || q.Response.ErrorCode == errNoDataFound {
+		return false
+	}
+	return q.Fetches > 0 || q.opensResultSet()
+}
+
+// opensResultSet checks if the query executes a statement returning rows, whatever its execution options.
+// The server describes the columns of result sets, statements parsed before the trace are recognized by their text.
+func (q *Query) opensResultSet() bool {
+	if q.Function == packet.OPARSE || q.Function == packet.OALL8 && q.ExeOp&exeOpExecute == 0 {
+		// Parsed only
+		return false
+	}
+	if q.Describe != nil && len(q.Describe.Columns) > 0 {
+		return true
+	}
+	words := strings.Fields(string(toUpperAscii([]byte(strings.TrimLeft(q.Query, " \t\r\n(")))))
+	return len(words) > 0 && (words[0] == "SELECT" || words[0] == "WITH")
+}
+
+// AverageFetch gives the average number of rows fetched per round trip
+func (q Query) AverageFetch() float64 {
+	if q.Fetches == 0 {
+		return 0
+	}
+	return float64(q.RowsFetched) / float64(q.Fetches)
+}
@@ -0,0 +1,238 @@
+package queries
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/simulot/oracle_trc/packet"
+	"github.com/simulot/oracle_trc/trc"
+)
+
+// Lob follows a LOB through the OLOBOPS calls done on its locator
+type Lob struct {
+	Type      OracleType // OCIClobLocator or OCIBlobLocator, 0 until a statement uses the locator
+	Locator   []byte     // Last locator sent for the LOB
+	Size      uint64     // Last known length: characters for CLOB, bytes for BLOB
+	Data      []byte     // Data read or written, in calls order
+	Charset   uint16     // Character set of CLOB data
+	Calls     int        // OLOBOPS round trips on the LOB
+	Temporary bool       // Created by the client as a temporary LOB
+
+	sent bool // A query using the LOB is sent, later calls go to a copy
+}
+
+// Character set of CLOB data in databases with a varying width character set
+const al16utf16 = 2000
+
+// Size of the shown part of LOB data
+const (
+	lobTextPreview = 1000
+	lobHexPreview  = 32
+)
+
+// String shows CLOB text or BLOB size and first bytes, when the data is in the trace
+func (l Lob) String() string {
+	switch {
+	case l.Type == OCIBlobLocator && l.Data != nil:
+		size := l.Size
+		if uint64(len(l.Data)) > size {
+			size = uint64(len(l.Data))
+		}
+		b, more := l.Data, ""
+		if len(b) > lobHexPreview {
+			b, more = b[:lobHexPreview], "..."
+		}
+		return fmt.Sprintf("BLOB(%d bytes) %X%s", size, b, more)
+	case l.Data != nil:
+		t, more := []rune(l.text()), ""
+		if len(t) > lobTextPreview {
+			t, more = t[:lobTextPreview], "..."
+		}
+		return "'" + strings.Replace(string(t), "'", "''", -1) + "'" + more
+	case l.Type == OCIBlobLocator && l.Size > 0:
+		return fmt.Sprintf("BLOB(%d bytes)", l.Size)
+	case l.Type == OCIClobLocator && l.Size > 0:
+		return fmt.Sprintf("CLOB(%d chars)", l.Size)
+	}
+	return "(" + l.Type.String() + ")"
+}
+
+// text decodes CLOB data
+func (l Lob) text() string {
+	if l.Charset != al16utf16 {
+		return string(l.Data)
+	}
+	u := make([]uint16, len(l.Data)/2)
+	for i := range u {
+		u[i] = uint16(l.Data[2*i])<<8 | uint16(l.Data[2*i+1])
+	}
+	return string(utf16.Decode(u))
+}
+
+// lobCall is an OLOBOPS call waiting for its response
+type lobCall struct {
+	call     packet.LobCall
+	data     []byte // Data written by the client
+	response *Response
+}
+
+// startLob remembers the LOB call of the client's packet and the data it writes
+func (s *Session) startLob(pk *trc.Packet) {
+	msgs, _ := s.requestContext().ReadMessages(pk.Payload)
+	for _, m := range msgs {
+		switch m := m.(type) {
+		case packet.LobCall:
+			s.lob = &lobCall{call: m, response: &Response{}}
+		case packet.LobData:
+			if s.lob != nil {
+				s.lob.data = m.Data
+			}
+		}
+	}
+}
+
+// endLob applies the LOB call and its response to the LOB of the locator
+func (p *Parser) endLob(s *Session) {
+	c := s.lob
+	if c == nil {
+		return
+	}
+	s.lob = nil
+
+	var l *Lob
+	if c.call.Operation == packet.LobCreateTemporary {
+		// The source is an empty locator, the server gives the new one
+		l = &Lob{Locator: c.call.Source, Temporary: true}
+	} else {
+		l = s.lobFor(c.call.Source)
+	}
+	l.Calls++
+	switch c.call.Operation {
+	case packet.LobWrite:
+		l.Data = append(l.Data, c.data...)
+	case packet.LobTrim:
+		l.Size = c.call.Size
+	}
+	if c.call.Charset != 0 {
+		l.Charset = c.call.Charset
+	}
+
+	ctx := s.responseContext(packet.OLOBOPS)
+	ctx.LobCall = c.call
+	msgs, _ := ctx.ReadMessages(c.response.payload())
+	for _, m := range msgs {
+		switch m := m.(type) {
+		case packet.LobData:
+			l.Data = append(l.Data, m.Data...)
+		case packet.LobParameters:
+			if m.Charset != 0 {
+				l.Charset = m.Charset
+			}
+			if c.call.SendSize && c.call.Operation == packet.LobGetLength {
+				l.Size = m.Size
+			}
+			if len(m.Source) > 0 && string(m.Source) != string(l.Locator) {
+				// The server gives a new locator, like for temporary LOBs
+				s.lobs[string(m.Source)] = l
+				l.Locator = m.Source
+			}
+		}
+	}
+}
+
+// lobFor gives the LOB of the locator, a new one is started when the locator is unknown
+// or when its LOB is already sent with a query.
+func (s *Session) lobFor(locator []byte) *Lob {
+	l, ok := s.lobs[string(locator)]
+	switch {
+	case !ok:
+		l = &Lob{Locator: locator}
+	case l.sent:
+		c := *l
+		c.sent = false
+		l = &c
+	default:
+		return l
+	}
+	s.lobs[string(locator)] = l
+	return l
+}
+
+// isLob checks if the value is a LOB locator
+func (p *ParameterInfo) isLob() bool {
+	return (p.DataType == OCIClobLocator || p.DataType == OCIBlobLocator) && !p.IsNull && len(p.Value) > 0
+}
+
+// values gives the binds of all iterations and the row values of the query
+func (q *Query) values() []*ParameterInfo {
+	values := append([]*ParameterInfo{}, q.Params...)
+	if q.IsBatch() {
+		for _, row := range q.BindRows[1:] {
+			values = append(values, row...)
+		}
+	}
+	if q.Response != nil {
+		for _, row := range q.Response.Rows {
+			values = append(values, row...)
+		}
+	}
+	return values
+}
+
+// attachLobs ties the LOB locators bound or fetched by the query to their LOB
+func (s *Session) attachLobs(q *Query) {
+	for _, v := range q.values() {
+		if v.isLob() && v.Lob == nil {
+			v.Lob = s.lobFor(v.Value)
+			v.Lob.Type = v.DataType
+		}
+	}
+}
+
+// readsLobs checks if the query fetched LOB locators, their data are read by following calls
+func (q *Query) readsLobs() bool {
+	if q.Response == nil {
+		return false
+	}
+	for _, row := range q.Response.Rows {
+		for _, v := range row {
+			if v.Lob != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sealLobs keeps the LOBs of the query as they are when the query is sent
+func (q *Query) sealLobs() {
+	for _, v := range q.values() {
+		if v.Lob != nil {
+			v.Lob.sent = true
+		}
+	}
+}
+
+// LobCalls counts the OLOBOPS round trips on the LOBs bound or fetched by the query
+func (q Query) LobCalls() int {
+	n := 0
+	seen := map[*Lob]bool{}
+	for _, v := range q.values() {
+		if v.Lob != nil && !seen[v.Lob] {
+			seen[v.Lob] = true
+			n += v.Lob.Calls
+		}
+	}
+	return n
+}
+
+// endReading sends the query whose LOBs were read
+func (p *Parser) endReading(s *Session) {
+	q := s.reading
+	if q == nil {
+		return
+	}
+	s.reading = nil
+	p.send(q)
+}
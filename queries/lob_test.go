@@ -0,0 +1,167 @@
+package queries
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_lobWrite(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=82.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=82
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 52 00 00 06 00 00 00  |.R......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 03 60 02 01 01 28  |...` + "`" + `...(|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 01 00 00 02  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 10 00 00 01 01 01 70  |.......p|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 26 00 54 00 00 00 00 00  |&.T.....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00 00 00 02  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 07 D0                    |..      |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: socket 1288 had bytes read=85
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 55 00 00 06 00 00 00  |.U......|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 08 00 26 00 04 00  |....&...|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 01 00 01 00 01 41 42 43  |.....ABC|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 44 45 46 47 48 49 4A 4B  |DEFGHIJK|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 4C 4D 4E 4F 50 51 52 53  |LMNOPQRS|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 54 55 56 57 58 59 5A 5B  |TUVWXYZ[|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 5C 5D 5E 02 07 D0 04 00  |\]^.....|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 09 00 01 03           |.....   |
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: exit: oln=0, dln=75, tot=85, rc=0
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: tot=0, plen=94.
+(5236) [22-OCT-2020 12:44:14:752] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfpwr: socket 1288 had bytes written=94
+(5236) [22-OCT-2020 12:44:14:752] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 00 5E 00 00 06 00 00 00  |.^......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 00 00 03 60 04 01 01 28  |...` + "`" + `...(|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 00 00 00 00 01 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 40 00 00 01 01 00 01 00  |@.......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 00 00 00 00 00 00 26 00  |......&.|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 04 00 01 00 01 00 01 41  |.......A|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 42 43 44 45 46 47 48 49  |BCDEFGHI|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 4A 4B 4C 4D 4E 4F 50 51  |JKLMNOPQ|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 52 53 54 55 56 57 58 59  |RSTUVWXY|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 5A 5B 5C 5D 5E 02 07 D0  |Z[\]^...|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 01 05 0E 0A 00 68 00 E9  |.....h..|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: 00 6C 00 6C 00 6F        |.l.l.o  |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: socket 1288 had bytes read=87
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 57 00 00 06 00 00 00  |.W......|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 08 00 26 00 04 00  |....&...|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 01 00 01 00 01 41 42 43  |.....ABC|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 44 45 46 47 48 49 4A 4B  |DEFGHIJK|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 4C 4D 4E 4F 50 51 52 53  |LMNOPQRS|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 54 55 56 57 58 59 5A 5B  |TUVWXYZ[|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 5C 5D 5E 02 07 D0 01 05  |\]^.....|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 04 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 00 09 00 01 05     |....... |
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: exit: oln=0, dln=77, tot=87, rc=0
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: tot=0, plen=179.
+(5236) [22-OCT-2020 12:44:14:754] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:754] nttfpwr: socket 1288 had bytes written=179
+(5236) [22-OCT-2020 12:44:14:754] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 B3 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 00 03 5E 16 02 80 29  |...^...)|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 49 4E 53 45 52 54 20 49  |INSERT.I|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 4E 54 4F 20 65 66 6C 6F  |NTO.eflo|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 77 5F 64 6F 63 75 6D 65  |w_docume|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 6E 74 73 20 28 64 6F 63  |nts.(doc|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 5F 69 64 2C 20 62 6F 64  |_id,.bod|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 79 29 20 56 41 4C 55 45  |y).VALUE|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 53 20 28 31 30 2C 20 3A  |S.(10,.:|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 31 29 01 01 00 00 00 00  |1)......|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 70 00 00 00 01 28 00  |.p....(.|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 01 10 00 00 02 07 D0 01  |........|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 00 07 28 00 26 00 04 00  |..(.&...|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 01 00 01 00 01 41 42 43  |.....ABC|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 44 45 46 47 48 49 4A 4B  |DEFGHIJK|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 4C 4D 4E 4F 50 51 52 53  |LMNOPQRS|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 54 55 56 57 58 59 5A 5B  |TUVWXYZ[|
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: 5C 5D 5E                 |\]^     |
+(5236) [22-OCT-2020 12:44:14:754] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:755] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:755] nttfprd: socket 1288 had bytes read=43
+(5236) [22-OCT-2020 12:44:14:755] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 2B 00 00 06 00 00 00  |.+......|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 00 04 01 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 01 02 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 00 00 00 00 00 00 09  |........|
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: 00 01 07                 |...     |
+(5236) [22-OCT-2020 12:44:14:755] nsbasic_brc: exit: oln=0, dln=33, tot=43, rc=0
+
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	if len(got) != 1 || len(got[0].Params) != 1 {
+		t.Errorf("Number of queries = %d, want 1 with its bind", len(got))
+		return
+	}
+	q := got[0]
+	l := q.Params[0].Lob
+	if l == nil || !l.Temporary || l.Calls != 2 {
+		t.Errorf("Lob = %+v, want a temporary LOB with 2 calls", l)
+		return
+	}
+	if s := q.Params[0].String(); s != "'héllo'" {
+		t.Errorf("Bind = %s, want 'héllo'", s)
+	}
+	if s := q.String(); !strings.HasSuffix(s, "  => 1 row(s), 2 LOB round trip(s)\n") {
+		t.Errorf("String() = %s", s)
+	}
+}
+
+func TestLob_String(t *testing.T) {
+	tests := []struct {
+		name string
+		l    Lob
+		want string
+	}{
+		{name: "CLOB", l: Lob{Type: OCIClobLocator, Data: []byte("it's")}, want: "'it''s'"},
+		{name: "CLOB length", l: Lob{Type: OCIClobLocator, Size: 12}, want: "CLOB(12 chars)"},
+		{name: "BLOB", l: Lob{Type: OCIBlobLocator, Size: 1000, Data: []byte{0xca, 0xfe}}, want: "BLOB(1000 bytes) CAFE"},
+		{name: "BLOB preview", l: Lob{Type: OCIBlobLocator, Data: make([]byte, 40)}, want: "BLOB(40 bytes) " + strings.Repeat("00", 32) + "..."},
+		{name: "not read", l: Lob{Type: OCIBlobLocator}, want: "(OCIBlobLocator)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,16 +5,19 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/simulot/oracle_trc/packet"
 	"github.com/simulot/oracle_trc/trc"
 )
 
 // Packet hold packet content and context of packet
 type Query struct {
-	Packet      *trc.Packet // Query's packet
-	Query       string      // Query text
+	Packet      *trc.Packet         // Query's packet
+	Function    packet.FunctionCode // Function called: OALL8 for statements, OALL7, OSQL7, OPARSE or OEXEC for V7 clients, OCOMMIT, OROLLBACK, OAQEQ, OAQDQ. 0 for connection events
+	Query       string              // Query text
 	ExeOp       uint32
 	CursorId    uint32
 	Len         uint32
@@ -22,37 +25,274 @@ type Query struct {
 	ParamLen    uint32
 	NbofDefCols uint32
 	Params      []*ParameterInfo
+	BindRows    [][]*ParameterInfo  // Bind values of each iteration, the first row is Params. Array DML sends several rows
+	Response    *Response           // Server's response, nil when not found in the trace
+	Err         *OracleError        // Error raised by the call, nil when it succeeded
+	Describe    *Describe           // Columns of the result set, nil when not described
+	Reexecuted  bool                // The client sent only the cursor id, the statement comes from the cursor table
+	Session     *Session            // Database session of the query
+	Event       string              // Connection event or non SQL call, empty for SQL statements
+	Cancelled   bool                // The client sent a break during the call
+	Interrupted bool                // The server broke the call, usually to report an error
+	Piggybacks  []packet.TTCMessage // Functions sent in front of the call, like cursor close
+	Fetches     int                 // Round trips that fetched rows: the execution, then the OFETCH calls
+	RowsFetched uint32              // Rows fetched by the execution and the OFETCH calls
+	Transaction *Transaction        // Transaction of the call, nil for connection events
+	PLSQL       string              // PL/SQL execution: anonymous block or call of a stored procedure, empty for SQL statements
+	EndToEnd    EndToEnd            // End-to-end attributes in effect when the call ran
+	AQ          *AQMessage          // Advanced Queuing enqueue or dequeue, nil for other calls
+	XA          *XACall             // XA call on a transaction branch, nil for other calls
+	XID         *packet.XID         // Transaction branch of the call, nil outside XA
+	RowCounts   bool                // The client asked the rows processed by each iteration of array DML
 }
 
+// Execution options bits
+const (
+	exeOpParse   uint32 = 0x01
+	exeOpBind    uint32 = 0x08
+	exeOpDefine  uint32 = 0x10
+	exeOpExecute uint32 = 0x20
+	exeOpFetch   uint32 = 0x40
+	exeOpCommit  uint32 = 0x100 // Commit on success
+)
+
+// errNoDataFound ends fetches, it's an error only for calls that don't fetch
+const errNoDataFound = 1403
+
 // String implement the basic representation of packet: Packet's context and its content in hexadecimal
 func (q Query) String() string {
 	sb := strings.Builder{}
 	q.Packet.WriteContext(&sb)
+	if q.Session != nil && q.Session.Login.User != "" {
+		sb.WriteString(fmt.Sprintf(" User(%s)", q.Session.Login.User))
+	}
 	writeEol(&sb)
+	if q.Event != "" {
+		sb.WriteString(q.Event)
+		writeEol(&sb)
+	}
+	if q.Query == "" {
+		if q.Err != nil {
+			sb.WriteString("  => ")
+			sb.WriteString(q.Err.Error())
+			writeEol(&sb)
+		}
+		return sb.String()
+	}
+	if e := q.EndToEnd.String(); e != "" {
+		sb.WriteString("  ")
+		sb.WriteString(e)
+		writeEol(&sb)
+	}
+	if q.XID != nil {
+		sb.WriteString("  XID ")
+		sb.WriteString(q.XID.String())
+		writeEol(&sb)
+	}
+	for _, m := range q.Piggybacks {
+		if s := piggybackString(m); s != "" {
+			sb.WriteString("  ")
+			sb.WriteString(s)
+			writeEol(&sb)
+		}
+	}
 	sb.WriteString(q.Query)
 	writeEol(&sb)
+	if q.PLSQL != "" {
+		sb.WriteString("  PL/SQL ")
+		sb.WriteString(q.PLSQL)
+		writeEol(&sb)
+	}
+	if q.IsBatch() {
+		sb.WriteString(fmt.Sprintf("  batch of %d rows, first one:", len(q.BindRows)))
+		writeEol(&sb)
+	}
 	for i, p := range q.Params {
-		sb.WriteString("  :")
-		sb.WriteString(strconv.Itoa(i + 1))
-		sb.WriteString(" = ")
-		switch p.DataType {
-		case CHAR:
-			sb.WriteByte('\'')
-			sb.WriteString(strings.Replace(p.String(), "'", "''", -1))
-			sb.WriteByte('\'')
-		default:
-			sb.WriteString(p.String())
+		if p.Direction != Output && p.Direction != RetVal {
+			sb.WriteString("  ")
+			sb.WriteString(p.bindName(i))
+			sb.WriteString(" = ")
+			sb.WriteString(p.quoted())
+			writeEol(&sb)
+		}
+		if p.OutValues != nil {
+			sb.WriteString("  ")
+			sb.WriteString(p.bindName(i))
+			sb.WriteString(" => ")
+			sb.WriteString(p.outString())
+			writeEol(&sb)
+		}
+	}
+	switch {
+	case q.Cancelled:
+		sb.WriteString("  => cancelled by the client")
+		writeEol(&sb)
+	case q.Interrupted:
+		sb.WriteString("  => interrupted by the server")
+		writeEol(&sb)
+	}
+	if q.Response != nil {
+		sb.WriteString("  => ")
+		if q.Err == nil && q.Response.ErrorCode == errNoDataFound {
+			// End of fetch
+			sb.WriteString(fmt.Sprintf("%d row(s)", q.Response.RowCount))
+		} else {
+			sb.WriteString(q.Response.String())
+		}
+		if q.Fetches > 1 {
+			sb.WriteString(fmt.Sprintf(" in %d fetches, %.1f rows per fetch", q.Fetches, q.AverageFetch()))
+		}
+		if q.CommitOnSuccess() && q.Err == nil {
+			sb.WriteString(", committed")
+		}
+		if n := q.LobCalls(); n > 0 {
+			sb.WriteString(fmt.Sprintf(", %d LOB round trip(s)", n))
 		}
 		writeEol(&sb)
 	}
 	return sb.String()
 }
 
+// Table gives the first n rows of the result set as a table, an empty string when there is no row
+func (q Query) Table(n int) string {
+	if q.Response == nil || len(q.Response.Rows) == 0 {
+		return ""
+	}
+	rows := q.Response.Rows
+	if n < len(rows) {
+		rows = rows[:n]
+	}
+
+	// Header, then values, all as strings
+	cells := make([][]string, 0, len(rows)+1)
+	header := []string{}
+	if q.Describe != nil {
+		for _, c := range q.Describe.Columns {
+			header = append(header, c.Name)
+		}
+	}
+	cells = append(cells, header)
+	for _, row := range rows {
+		line := make([]string, len(row))
+		for i, v := range row {
+			line[i] = v.String()
+		}
+		cells = append(cells, line)
+	}
+	return formatTable(cells, len(q.Response.Rows)-len(rows))
+}
+
+// IsBatch checks if the call is an array DML, binding several rows of values
+func (q Query) IsBatch() bool {
+	return len(q.BindRows) > 1
+}
+
+// Batch gives the first n rows of values of an array DML as a table, with the rows processed by each iteration
+// when the server sent them. An empty string is returned when the call has a single row of values.
+func (q Query) Batch(n int) string {
+	if !q.IsBatch() {
+		return ""
+	}
+	rows := q.BindRows
+	if n < len(rows) {
+		rows = rows[:n]
+	}
+	var counts []uint64
+	if q.Response != nil {
+		counts = q.Response.RowCounts
+	}
+
+	header := []string{"#"}
+	for i, p := range q.Params {
+		header = append(header, p.bindName(i))
+	}
+	if len(counts) > 0 {
+		header = append(header, "rows")
+	}
+	cells := [][]string{header}
+	for r, row := range rows {
+		line := []string{strconv.Itoa(r + 1)}
+		for _, v := range row {
+			line = append(line, v.quoted())
+		}
+		if r < len(counts) {
+			line = append(line, strconv.FormatUint(counts[r], 10))
+		}
+		cells = append(cells, line)
+	}
+	return formatTable(cells, len(q.BindRows)-len(rows))
+}
+
+// formatTable aligns the cells in columns, the first line is the header.
+// The number of rows not shown is written below the table.
+func formatTable(cells [][]string, more int) string {
+	widths := []int{}
+	for _, line := range cells {
+		for i, c := range line {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			if len(c) > widths[i] {
+				widths[i] = len(c)
+			}
+		}
+	}
+
+	sb := strings.Builder{}
+	for l, line := range cells {
+		sb.WriteString("  ")
+		for i, c := range line {
+			if i > 0 {
+				sb.WriteString(" | ")
+			}
+			sb.WriteString(c)
+			if i < len(line)-1 {
+				sb.WriteString(strings.Repeat(" ", widths[i]-len(c)))
+			}
+		}
+		writeEol(&sb)
+		if l == 0 {
+			sb.WriteString("  ")
+			for i, w := range widths {
+				if i > 0 {
+					sb.WriteString("-+-")
+				}
+				sb.WriteString(strings.Repeat("-", w))
+			}
+			writeEol(&sb)
+		}
+	}
+	if more > 0 {
+		sb.WriteString(fmt.Sprintf("  ... %d more row(s)", more))
+		writeEol(&sb)
+	}
+	return sb.String()
+}
+
+// piggybackString describes a piggyback, an empty string is returned for the ones not decoded
+func piggybackString(m packet.TTCMessage) string {
+	switch m := m.(type) {
+	case packet.CursorClose:
+		ids := make([]string, len(m.Ids))
+		for i, id := range m.Ids {
+			ids[i] = strconv.Itoa(int(id))
+		}
+		return "close cursors " + strings.Join(ids, ", ")
+	case packet.CancelAll:
+		return "cancel all"
+	case packet.SessionState:
+		return "session state update"
+	}
+	return ""
+}
+
 // Parser is used to parse trc files and extract queries
 type Parser struct {
-	p     *trc.Parser // Trace file parser
-	q     *Query      // current query
-	qChan chan queryAndError
+	p        *trc.Parser // Trace file parser
+	q        *Query      // current query
+	qChan    chan queryAndError
+	sessions map[int]*Session // Opened sessions per socket
+	sessionN int              // Number of sessions seen
 }
 
 type queryAndError struct {
@@ -63,8 +303,9 @@ type queryAndError struct {
 // New create a trc parser
 func New(r io.Reader, name string) *Parser {
 	p := &Parser{
-		p:     trc.New(r, name),
-		qChan: make(chan queryAndError),
+		p:        trc.New(r, name),
+		qChan:    make(chan queryAndError),
+		sessions: make(map[int]*Session),
 	}
 
 	go func() {
@@ -92,164 +333,420 @@ func waitQuery(p *Parser) stateFn {
 		if pk == nil && err == nil {
 			break
 		}
-		if pk.Typ == "nsbasic_bsd" {
-			return p.parseQuery(pk)
-		}
 		if err != nil {
 			fmt.Println(err)
 		}
+		if pk == nil {
+			continue
+		}
+		if pk.Typ == trc.Disconnect {
+			if s, ok := p.sessions[pk.Socket]; ok {
+				s.addPacket(pk)
+				s.Closed = true
+				p.closeSession(s)
+			}
+			continue
+		}
+		s := p.session(pk)
+		if s.continued && len(s.request) > 0 {
+			// Next packet of a call larger than the SDU
+			s.request = append(s.request, pk)
+			continue
+		}
+		p.parseRequest(s)
+		if event := connectionEvent(pk); event != "" {
+			p.emitEvent(s, pk, event)
+			continue
+		}
+		if s.mark(pk) {
+			continue
+		}
+		switch pk.Typ {
+		case "nsbasic_bsd":
+			// A new call on the socket ends the response of the previous one
+			p.emitPending(s)
+			p.endFetch(s)
+			p.endLob(s)
+			for _, id := range s.closeCursors() {
+				p.endFetching(s, id)
+			}
+			if s.call != packet.OLOBOPS {
+				p.endReading(s)
+			}
+			// The call is parsed once all its packets are received
+			s.request = []*trc.Packet{pk}
+		case "nsbasic_brc":
+			if q := s.pending; q != nil {
+				if q.Response == nil {
+					q.Response = &Response{}
+				}
+				q.Response.addPacket(pk)
+			} else if f := s.fetch; f != nil {
+				f.response.addPacket(pk)
+			} else if c := s.lob; c != nil {
+				c.response.addPacket(pk)
+			}
+		}
 	}
+	p.closeAllSessions()
 	return nil
 }
 
-// parseQuery and returns the next stateFn
-func (p *Parser) parseQuery(pk *trc.Packet) stateFn {
+// session returns the session of the packet's socket, a new session is opened when needed
+func (p *Parser) session(pk *trc.Packet) *Session {
+	s, ok := p.sessions[pk.Socket]
+	if ok && s.connected && isConnect(pk) {
+		// The socket is reused for a new connection
+		p.closeSession(s)
+		ok = false
+	}
+	if !ok {
+		p.sessionN++
+		s = newSession(p.sessionN, pk)
+		p.sessions[pk.Socket] = s
+	}
+	s.addPacket(pk)
+	return s
+}
 
-	var err error
-	var b byte
+// closeSession sends the pending query and the ones still fetching, then forget the session
+func (p *Parser) closeSession(s *Session) {
+	p.parseRequest(s)
+	p.emitPending(s)
+	p.endFetch(s)
+	p.endLob(s)
+	for _, q := range s.Queries {
+		if s.open[q.CursorId] == q {
+			p.endFetching(s, q.CursorId)
+		}
+	}
+	p.endReading(s)
+	if len(s.Queries) == 0 {
+		// Sessions without statements are reported by an event, they would be missing from the output otherwise
+		q := &Query{
+			Packet:  s.Packets[len(s.Packets)-1],
+			Session: s,
+			Event:   s.closingEvent(),
+			Err:     s.LoginErr,
+		}
+		s.Queries = append(s.Queries, q)
+		p.qChan <- queryAndError{
+			q: q,
+		}
+	}
+	delete(p.sessions, s.Socket)
+}
 
+// closeAllSessions sends all waiting queries in trace file order
+func (p *Parser) closeAllSessions() {
+	sessions := make([]*Session, 0, len(p.sessions))
+	for _, s := range p.sessions {
+		sessions = append(sessions, s)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Id < sessions[j].Id
+	})
+	for _, s := range sessions {
+		p.closeSession(s)
+	}
+}
+
+// emitEvent sends an event that isn't a SQL statement
+func (p *Parser) emitEvent(s *Session, pk *trc.Packet, event string) {
 	q := &Query{
-		Packet: pk,
+		Packet:  pk,
+		Session: s,
+		Event:   event,
+	}
+	s.Queries = append(s.Queries, q)
+	p.qChan <- queryAndError{
+		q: q,
+	}
+}
+
+// emitPending sends the query waiting its response on the session
+func (p *Parser) emitPending(s *Session) {
+	q := s.pending
+	if q == nil {
+		return
 	}
+	s.pending = nil
+
+	if q.Response != nil {
+		if cached := s.cursors[q.CursorId]; q.Reexecuted && cached != nil {
+			// The columns are described when the cursor is parsed
+			q.Describe = cached.Describe
+		}
+		ctx := s.responseContext(q.Function)
+		ctx.RowCounts = q.IsBatch() || q.RowCounts
+		ctx.Binds = q.bindColumns()
+		if cols := q.returningColumns(); len(cols) > 0 {
+			ctx.Returning = true
+			ctx.Columns = cols
+		}
+		q.Describe = q.Response.decode(ctx, q.Describe)
+		q.setOutBinds()
+		if q.AQ != nil {
+			q.AQ.received(q.Response)
+			q.Event = q.AQ.String()
+		}
+		if q.XA != nil {
+			q.XA.received(q.Response)
+			q.Event = q.XA.String()
+		}
+		q.Err = q.callError()
+		s.switchBranch(q)
+		s.registerCursor(q)
+		s.attachLobs(q)
+		if q.ExeOp&exeOpFetch != 0 && q.Err == nil {
+			q.Fetches = 1
+			q.RowsFetched = q.Response.RowCount
+		}
+	}
+	s.Queries = append(s.Queries, q)
+	s.track(q)
+	if q.fetching() {
+		// Sent once all rows are fetched, or when the cursor is closed or reused
+		p.endFetching(s, q.CursorId)
+		s.open[q.CursorId] = q
+		return
+	}
+	p.release(s, q)
+}
+
+// release sends the query, unless the client may read its LOBs with the next calls
+func (p *Parser) release(s *Session, q *Query) {
+	p.endReading(s)
+	if q.readsLobs() {
+		s.reading = q
+		return
+	}
+	p.send(q)
+}
 
-	if len(pk.Payload) < 0x20 {
-		return waitQuery
+// send delivers the query to the parser's reader
+func (p *Parser) send(q *Query) {
+	q.sealLobs()
+	p.qChan <- queryAndError{
+		q: q,
 	}
+}
 
-	pl := pk.Payload
-	buff := bytes.NewBuffer(pl[0x12:])
-	b, err = buff.ReadByte()
+// callError gives the error raised by the call, the end of fetch isn't an error
+func (q *Query) callError() *OracleError {
+	err := q.Response.Err()
+	if err != nil && err.Code == errNoDataFound && q.ExeOp&exeOpFetch != 0 {
+		return nil
+	}
+	return err
+}
 
-	// Field 1 Marker
-	if err == nil && b == 0x03 {
-		b, err = buff.ReadByte()
-		if err != nil || b != 0x5E {
-			return waitQuery
+// mainCall finds the function call of the client's packet and the piggybacks sent in front of it
+func (s *Session) mainCall(pk *trc.Packet) (packet.Caller, []packet.TTCMessage, bool) {
+	msgs, err := s.requestContext().ReadMessages(pk.Payload)
+	var piggybacks []packet.TTCMessage
+	for _, m := range msgs {
+		if call, ok := m.(packet.Caller); ok {
+			return call, piggybacks, err == nil
+		}
+		if m.Code() == packet.TTIPFN {
+			piggybacks = append(piggybacks, m)
 		}
-	} else {
-		return waitQuery
 	}
-	if err != nil {
-		return waitQuery
+	return nil, piggybacks, false
+}
+
+// parseRequest parses the client's call, its packets are joined
+func (p *Parser) parseRequest(s *Session) {
+	if len(s.request) == 0 {
+		return
+	}
+	pk := s.request[0]
+	if len(s.request) > 1 {
+		joined := *pk
+		joined.Payload = joinPackets(s.request)
+		pk = &joined
 	}
+	s.request = nil
+	p.parseQuery(s, pk)
+}
 
-	// Field 2 Flag
-	b, err = buff.ReadByte() // discard Flag
-	if err != nil {
-		return waitQuery
+// parseQuery decodes the client's call, the query waits its response
+func (p *Parser) parseQuery(s *Session, pk *trc.Packet) {
+
+	var err error
+
+	q := &Query{
+		Packet:  pk,
+		Session: s,
 	}
 
-	// Field 3 ExeOp
-	q.ExeOp, err = GetUInt(buff, 4, true, true) // Read ExeOp
-	if err != nil {
-		return waitQuery
+	caller, piggybacks, ok := s.mainCall(pk)
+	q.Piggybacks = piggybacks
+	s.EndToEnd.apply(piggybacks)
+	q.EndToEnd = s.EndToEnd
+	q.XID = s.XID
+	if !ok {
+		return
+	}
+	switch c := caller.(type) {
+	case packet.FetchCall:
+		s.startFetch(c)
+		return
+	case packet.LobCall:
+		s.startLob(pk)
+		return
+	}
+	call := caller.Call()
+	q.Function = call.Function
+	switch call.Function {
+	case packet.OALL8:
+	case packet.OALL7, packet.OSQL7, packet.OPARSE, packet.OEXEC:
+		p.parseV7Call(s, q, call)
+		return
+	case packet.OCOMMIT:
+		q.Event = "commit"
+		s.pending = q
+		return
+	case packet.OROLLBACK:
+		q.Event = "rollback"
+		s.pending = q
+		return
+	case packet.OAQEQ, packet.OAQDQ:
+		q.AQ = newAQMessage(caller)
+		if q.AQ == nil {
+			// Body not decoded
+			return
+		}
+		q.Event = q.AQ.String()
+		s.pending = q
+		return
+	case packet.OTXSE, packet.OTXEN:
+		q.XA = newXACall(caller)
+		if q.XA == nil {
+			// Operation not known
+			return
+		}
+		if q.XA.XID == nil {
+			// The branch is given by the transaction context
+			q.XA.XID = s.XID
+		}
+		q.XID = q.XA.XID
+		q.Event = q.XA.String()
+		s.pending = q
+		return
+	default:
+		return
 	}
+	// Function code and sequence number are read by the TTC layer
+	buff := bytes.NewBuffer(call.Body)
 
-	// Field 4 Cursor ID
-	q.CursorId, err = GetUInt(buff, 2, true, true) // Read Cursor ID
+	// Execution options
+	q.ExeOp, err = packet.GetUInt(buff, 4, true, true)
 	if err != nil {
-		return waitQuery
+		return
 	}
 
-	// Field 5
-	b, err = buff.ReadByte() // discard byte after cursor id
+	// Cursor id, 0 when the statement is parsed on a new cursor
+	q.CursorId, err = packet.GetUInt(buff, 4, true, true)
 	if err != nil {
-		return waitQuery
+		return
 	}
+	// A new call on the cursor ends the fetches of the previous execution
+	p.endFetching(s, q.CursorId)
 
-	// Field 6, statment length ?
-	q.Len, err = GetUInt(buff, 4, true, true) // Read Statment length ??
+	// Statement pointer and length, the length is 0 for the re-execution of a cursor
+	err = packet.SkipUInts(buff, 1)
 	if err != nil {
-		return waitQuery
+		return
 	}
-
-	// Field 7
-	b, err = buff.ReadByte() // discard byte after statment length
+	q.Len, err = packet.GetUInt(buff, 4, true, true)
 	if err != nil {
-		return waitQuery
+		return
 	}
 
-	var discardedInt int32
-	// Field 8 always 13???
-	discardedInt, err = GetInt(buff, 2, true, true) // Is always 13, purpose?.
+	// AL8I4 pointer and number of ints, 13 for all known versions
+	err = packet.SkipUInts(buff, 1)
 	if err != nil {
-		return waitQuery
+		return
+	}
+	var al8i4Len uint32
+	al8i4Len, err = packet.GetUInt(buff, 4, true, true)
+	if err != nil || al8i4Len > maxAL8I4 {
+		return
 	}
 
-	// Field 9
-	b, err = buff.ReadByte()
+	// AL8O4 and its length pointers, prefetch buffer size
+	err = packet.SkipUInts(buff, 1, 1, 4)
 	if err != nil {
-		return waitQuery
+		return
 	}
 
-	// Field 10
-	b, err = buff.ReadByte()
+	// Rows to fetch
+	q.RowToFetch, err = packet.GetUInt(buff, 4, true, true)
 	if err != nil {
-		return waitQuery
+		return
 	}
 
-	// Field 11
-	discardedInt, err = GetInt(buff, 4, true, true)
+	// Max long size
+	err = packet.SkipUInts(buff, 4)
 	if err != nil {
-		return waitQuery
+		return
 	}
 
-	// Field 12
-	q.RowToFetch, err = GetUInt(buff, 4, true, true) // row to fetch
+	// Binds pointer and number of binds, present even when the pointer is 0
+	err = packet.SkipUInts(buff, 1)
+	if err != nil {
+		return
+	}
+	q.ParamLen, err = packet.GetUInt(buff, 4, true, true)
 	if err != nil {
-		return waitQuery
+		return
 	}
 
-	// Field 13
-	discardedInt, err = GetInt(buff, 4, true, true) // Should be 0, unknown
+	// Application context, transaction, key/values, defines, registration id,
+	// object list, bulk list, database name and registration id MSB
+	err = packet.SkipUInts(buff, 1, 1, 1, 1, 1, 1, 4, 4, 1, 1, 1, 4, 1, 4, 4)
 	if err != nil {
-		return waitQuery
+		return
 	}
 
-	// Field 14 Has Parameters == 1
-	b, err = buff.ReadByte() // Paramter flag
+	// Fields added by later versions
+	var ext oall8Extensions
+	ext, err = readOALL8Extensions(buff, s.oall8Version(buff, q.Len > 0))
 	if err != nil {
-		return waitQuery
+		return
 	}
+	q.RowCounts = ext.rowCounts
 
-	if b > 0 {
-		// Field 15
-		q.ParamLen, err = GetUInt(buff, 2, true, true)
+	// The statement is sent only when the cursor is parsed.
+	// Otherwise, it's a re-execution of a cached cursor
+	if q.Len > 0 {
+		var stmt []byte
+		stmt, err = packet.ReadBytes(buff)
 		if err != nil {
-			return waitQuery
+			return
 		}
+		q.Query = string(stmt)
 	}
 
-	// Detect query start... sort of.
-
-	for {
-		b, err = buff.ReadByte()
+	// Structure AL8I4, the second int is the number of iterations
+	al8i4 := make([]uint32, al8i4Len)
+	for i := range al8i4 {
+		al8i4[i], err = packet.GetUInt(buff, 4, true, true)
 		if err != nil {
-			return waitQuery
-		}
-		if b > 5 {
-			// One step back
-			err = buff.UnreadByte()
-			if err != nil {
-				return waitQuery
-			}
-			break
+			return
 		}
 	}
-
-	var stmt []byte
-	stmt, err = readBytes(buff)
-	if err != nil {
-		return waitQuery
+	iterations := 1
+	if len(al8i4) > 1 {
+		iterations = int(al8i4[1])
 	}
-	q.Query = string(stmt)
 
-	// Skip 13 int for structure AL8I4
-	for i := 0; i < 13; i++ {
-		discardedInt, err = GetInt(buff, 2, true, true)
-		if err != nil {
-			return waitQuery
-		}
+	// SQL signature, SQL id and chunk ids sent by later versions
+	err = ext.skipContents(buff)
+	if err != nil {
+		return
 	}
 
 	if q.ParamLen > 0 {
@@ -258,34 +755,38 @@ func (p *Parser) parseQuery(pk *trc.Packet) stateFn {
 			var p *ParameterInfo
 			p, err = GetParamInfo(buff)
 			if err != nil {
-				return waitQuery
+				return
 			}
+			p.WireType = s.wireType(p.DataType)
 			q.Params = append(q.Params, p)
 		}
+	}
 
-		// Skip byte 7
-		b, err = buff.ReadByte()
-		if err != nil {
-			return waitQuery
+	if q.Len == 0 {
+		cached := s.cursors[q.CursorId]
+		if cached == nil {
+			// Cursor opened before the beginning of the trace
+			return
 		}
-
-		for _, p := range q.Params {
-			var v []byte
-			v, err = readBytes(buff)
-			if err != nil {
-				return waitQuery
-			}
-			p.Value = v
+		q.Query = cached.Query
+		q.Reexecuted = true
+		if q.ParamLen == 0 && q.ExeOp&exeOpBind != 0 {
+			// Binds are the same as the previous execution, only values are sent
+			q.reuseBinds(cached)
 		}
 	}
 
-	p.qChan <- queryAndError{
-		q:   q,
-		err: err,
+	if len(q.Params) > 0 {
+		err = q.readBindRows(buff, iterations)
+		if err != nil {
+			return
+		}
 	}
 
-	_ = discardedInt
-	return waitQuery
+	q.PLSQL = plsqlKind(q.Query)
+	q.nameBinds()
+	q.setDirections()
+	s.pending = q
 }
 
 type EndianNess int
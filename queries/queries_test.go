@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+
+	"github.com/simulot/oracle_trc/packet"
 )
 
 func Test_toUpperAscii(t *testing.T) {
@@ -108,6 +110,45 @@ func Test_getQueryFromTraceSnippet(t *testing.T) {
 (5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
 (5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 44 41 54 45              |DATE    |
 (5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+`,
+			},
+			want: want{
+				"SELECT param_value FROM eflow_params WHERE param_name = :1",
+				[]string{
+					"LICEXPIRATIONDATE",
+				},
+			},
+		},
+		{
+			name: "select without piggyback",
+			args: args{
+				trc: `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=156.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=156
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 9C 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
 `,
 			},
 			want: want{
@@ -867,3 +908,640 @@ from
 		})
 	}
 }
+
+// getQueriesFromTraceSnippet is an helper to get all sql queries from trace
+func getQueriesFromTraceSnippet(trc string) ([]*Query, error) {
+	p := New(strings.NewReader(trc), "test")
+	qs := []*Query{}
+	for {
+		q, err := p.Next()
+		if err != nil {
+			return qs, err
+		}
+		if q == nil {
+			return qs, nil
+		}
+		qs = append(qs, q)
+	}
+}
+
+func Test_cachedCursorReexecution(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=51
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 33 00 00 06 00 00 00  |.3......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 08 01 03 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 04 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 09 00 00                 |...     |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=41, tot=51, rc=0
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: tot=0, plen=81.
+(5236) [22-OCT-2020 12:44:15:120] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:15:120] nttfpwr: socket 1288 had bytes written=81
+(5236) [22-OCT-2020 12:44:15:120] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: 00 51 00 00 06 00 00 00  |.Q......|
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: 00 00 11 69 1F 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: 01 01 03 5E 20 02 80 28  |...^...(|
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: 01 02 00 00 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: 01 00 01 64 00 00 00 00  |...d....|
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: 01 00 01 01 01 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: 01 00 00 00 00 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: 01 00 00 00 00 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: 00 00 00 00 00 07 0A 4F  |.......O|
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: 54 48 45 52 56 41 4C 55  |THERVALU|
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: 45                       |E       |
+(5236) [22-OCT-2020 12:44:15:120] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:15:121] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:15:121] nttfprd: entry
+(5236) [22-OCT-2020 12:44:15:121] nttfprd: socket 1288 had bytes read=51
+(5236) [22-OCT-2020 12:44:15:121] nttfprd: exit
+(5236) [22-OCT-2020 12:44:15:121] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:15:121] nsbasic_brc: 00 33 00 00 06 00 00 00  |.3......|
+(5236) [22-OCT-2020 12:44:15:121] nsbasic_brc: 00 00 08 01 03 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:15:121] nsbasic_brc: 00 00 00 04 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:121] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:121] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:121] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:121] nsbasic_brc: 09 00 00                 |...     |
+(5236) [22-OCT-2020 12:44:15:121] nsbasic_brc: exit: oln=0, dln=41, tot=51, rc=0
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	if len(got) != 2 {
+		t.Errorf("Number of queries = %d, want 2", len(got))
+		return
+	}
+	want := []struct {
+		cursorId   uint32
+		reexecuted bool
+		param      string
+	}{
+		{2, false, "LICEXPIRATIONDATE"},
+		{2, true, "OTHERVALUE"},
+	}
+	for i, w := range want {
+		q := got[i]
+		if !significantCharsEqual(q.Query, "SELECT param_value FROM eflow_params WHERE param_name = :1") {
+			t.Errorf("Query #%d = %v", i+1, q.Query)
+		}
+		if q.CursorId != w.cursorId || q.Reexecuted != w.reexecuted {
+			t.Errorf("Query #%d cursor = %d, reexecuted = %t, want %d, %t", i+1, q.CursorId, q.Reexecuted, w.cursorId, w.reexecuted)
+		}
+		if len(q.Params) != 1 || q.Params[0].String() != w.param {
+			t.Errorf("Query #%d parameters = %v, want %s", i+1, q.Params, w.param)
+		}
+	}
+}
+
+func Test_cursorClose(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:15:100] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:15:100] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:15:100] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:15:100] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:15:102] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:15:102] nttfprd: entry
+(5236) [22-OCT-2020 12:44:15:102] nttfprd: socket 1288 had bytes read=51
+(5236) [22-OCT-2020 12:44:15:102] nttfprd: exit
+(5236) [22-OCT-2020 12:44:15:102] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:15:102] nsbasic_brc: 00 33 00 00 06 00 00 00  |.3......|
+(5236) [22-OCT-2020 12:44:15:102] nsbasic_brc: 00 00 08 01 03 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:15:102] nsbasic_brc: 00 00 00 04 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:102] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:102] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:102] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:102] nsbasic_brc: 09 00 00                 |...     |
+(5236) [22-OCT-2020 12:44:15:102] nsbasic_brc: exit: oln=0, dln=41, tot=51, rc=0
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: tot=0, plen=81.
+(5236) [22-OCT-2020 12:44:15:104] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:15:104] nttfpwr: socket 1288 had bytes written=81
+(5236) [22-OCT-2020 12:44:15:104] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: 00 51 00 00 06 00 00 00  |.Q......|
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: 00 00 11 69 1F 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: 02 01 03 5E 20 02 80 28  |...^...(|
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: 01 02 00 00 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: 01 00 01 64 00 00 00 00  |...d....|
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: 01 00 01 01 01 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: 01 00 00 00 00 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: 01 00 00 00 00 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: 00 00 00 00 00 07 0A 4F  |.......O|
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: 54 48 45 52 56 41 4C 55  |THERVALU|
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: 45                       |E       |
+(5236) [22-OCT-2020 12:44:15:104] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:15:106] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:15:106] nttfprd: entry
+(5236) [22-OCT-2020 12:44:15:106] nttfprd: socket 1288 had bytes read=51
+(5236) [22-OCT-2020 12:44:15:106] nttfprd: exit
+(5236) [22-OCT-2020 12:44:15:106] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:15:106] nsbasic_brc: 00 33 00 00 06 00 00 00  |.3......|
+(5236) [22-OCT-2020 12:44:15:106] nsbasic_brc: 00 00 08 01 03 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:15:106] nsbasic_brc: 00 00 00 04 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:106] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:106] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:106] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:106] nsbasic_brc: 09 00 00                 |...     |
+(5236) [22-OCT-2020 12:44:15:106] nsbasic_brc: exit: oln=0, dln=41, tot=51, rc=0
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:15:108] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:15:108] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:15:108] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:15:108] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:15:110] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:15:110] nttfprd: entry
+(5236) [22-OCT-2020 12:44:15:110] nttfprd: socket 1288 had bytes read=51
+(5236) [22-OCT-2020 12:44:15:110] nttfprd: exit
+(5236) [22-OCT-2020 12:44:15:110] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:15:110] nsbasic_brc: 00 33 00 00 06 00 00 00  |.3......|
+(5236) [22-OCT-2020 12:44:15:110] nsbasic_brc: 00 00 08 01 03 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:15:110] nsbasic_brc: 00 00 00 04 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:110] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:110] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:110] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:110] nsbasic_brc: 09 00 00                 |...     |
+(5236) [22-OCT-2020 12:44:15:110] nsbasic_brc: exit: oln=0, dln=41, tot=51, rc=0
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: tot=0, plen=81.
+(5236) [22-OCT-2020 12:44:15:112] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:15:112] nttfpwr: socket 1288 had bytes written=81
+(5236) [22-OCT-2020 12:44:15:112] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: 00 51 00 00 06 00 00 00  |.Q......|
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: 00 00 11 69 1F 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: 01 01 03 5E 20 02 80 28  |...^...(|
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: 01 02 00 00 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: 01 00 01 64 00 00 00 00  |...d....|
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: 01 00 01 01 01 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: 01 00 00 00 00 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: 01 00 00 00 00 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: 00 00 00 00 00 07 0A 4F  |.......O|
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: 54 48 45 52 56 41 4C 55  |THERVALU|
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: 45                       |E       |
+(5236) [22-OCT-2020 12:44:15:112] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:15:114] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:15:114] nttfprd: entry
+(5236) [22-OCT-2020 12:44:15:114] nttfprd: socket 1288 had bytes read=51
+(5236) [22-OCT-2020 12:44:15:114] nttfprd: exit
+(5236) [22-OCT-2020 12:44:15:114] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:15:114] nsbasic_brc: 00 33 00 00 06 00 00 00  |.3......|
+(5236) [22-OCT-2020 12:44:15:114] nsbasic_brc: 00 00 08 01 03 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:15:114] nsbasic_brc: 00 00 00 04 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:114] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:114] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:114] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:114] nsbasic_brc: 09 00 00                 |...     |
+(5236) [22-OCT-2020 12:44:15:114] nsbasic_brc: exit: oln=0, dln=41, tot=51, rc=0
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	// The re-execution of the closed cursor can't be resolved
+	if len(got) != 3 {
+		t.Errorf("Number of queries = %d, want 3", len(got))
+		return
+	}
+	q := got[2]
+	if !q.Reexecuted || q.CursorId != 2 {
+		t.Errorf("Query #3 cursor = %d, reexecuted = %t, want 2, true", q.CursorId, q.Reexecuted)
+	}
+	if want := []packet.TTCMessage{packet.CursorClose{Sequence: 0x1f, Ids: []uint32{1}}}; !reflect.DeepEqual(q.Piggybacks, want) {
+		t.Errorf("Query #3 piggybacks = %v, want %v", q.Piggybacks, want)
+	}
+	if !strings.Contains(q.String(), "  close cursors 1\n") {
+		t.Errorf("String() = %s", q.String())
+	}
+}
+
+func Test_breakAndReset(t *testing.T) {
+	tests := []struct {
+		name        string
+		trc         string
+		cancelled   bool
+		interrupted bool
+		errorCode   uint32
+	}{
+		{
+			name: "cancelled by the client",
+			trc: `(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:10:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:10:750] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:10:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:10:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:11:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:11:750] nsbasic_bsd: tot=0, plen=11.
+(5236) [22-OCT-2020 12:44:11:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:11:750] nttfpwr: socket 1288 had bytes written=11
+(5236) [22-OCT-2020 12:44:11:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:11:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:11:750] nsbasic_bsd: 00 0B 00 00 0C 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:11:750] nsbasic_bsd: 01 00 01                 |...     |
+(5236) [22-OCT-2020 12:44:11:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:12:750] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:12:750] nttfprd: entry
+(5236) [22-OCT-2020 12:44:12:750] nttfprd: socket 1288 had bytes read=11
+(5236) [22-OCT-2020 12:44:12:750] nttfprd: exit
+(5236) [22-OCT-2020 12:44:12:750] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:12:750] nsbasic_brc: 00 0B 00 00 0C 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:12:750] nsbasic_brc: 01 00 01                 |...     |
+(5236) [22-OCT-2020 12:44:12:750] nsbasic_brc: exit: oln=0, dln=1, tot=11, rc=0
+(5236) [22-OCT-2020 12:44:13:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:13:750] nsbasic_bsd: tot=0, plen=11.
+(5236) [22-OCT-2020 12:44:13:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:13:750] nttfpwr: socket 1288 had bytes written=11
+(5236) [22-OCT-2020 12:44:13:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:13:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:13:750] nsbasic_bsd: 00 0B 00 00 0C 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:13:750] nsbasic_bsd: 01 00 02                 |...     |
+(5236) [22-OCT-2020 12:44:13:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:750] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfprd: socket 1288 had bytes read=11
+(5236) [22-OCT-2020 12:44:14:750] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_brc: 00 0B 00 00 0C 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_brc: 01 00 02                 |...     |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_brc: exit: oln=0, dln=1, tot=11, rc=0
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:15:750] nttfprd: entry
+(5236) [22-OCT-2020 12:44:15:750] nttfprd: socket 1288 had bytes read=95
+(5236) [22-OCT-2020 12:44:15:750] nttfprd: exit
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: 00 5F 00 00 06 00 00 00  |._......|
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: 00 00 04 00 02 03 F5 00  |........|
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: 36 4F 52 41 2D 30 31 30  |6ORA-010|
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: 31 33 3A 20 75 73 65 72  |13:.user|
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: 20 72 65 71 75 65 73 74  |.request|
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: 65 64 20 63 61 6E 63 65  |ed.cance|
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: 6C 20 6F 66 20 63 75 72  |l.of.cur|
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: 72 65 6E 74 20 6F 70 65  |rent.ope|
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: 72 61 74 69 6F 6E 0A     |ration. |
+(5236) [22-OCT-2020 12:44:15:750] nsbasic_brc: exit: oln=0, dln=85, tot=95, rc=0
+`,
+			cancelled: true,
+			errorCode: 1013,
+		},
+		{
+			name: "interrupted by the server",
+			trc: `(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:45:10:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:45:10:750] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:45:10:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:45:10:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:45:11:750] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:45:11:750] nttfprd: entry
+(5236) [22-OCT-2020 12:45:11:750] nttfprd: socket 1288 had bytes read=11
+(5236) [22-OCT-2020 12:45:11:750] nttfprd: exit
+(5236) [22-OCT-2020 12:45:11:750] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:45:11:750] nsbasic_brc: 00 0B 00 00 0C 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:11:750] nsbasic_brc: 01 00 01                 |...     |
+(5236) [22-OCT-2020 12:45:11:750] nsbasic_brc: exit: oln=0, dln=1, tot=11, rc=0
+(5236) [22-OCT-2020 12:45:12:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:45:12:750] nsbasic_bsd: tot=0, plen=11.
+(5236) [22-OCT-2020 12:45:12:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:45:12:750] nttfpwr: socket 1288 had bytes written=11
+(5236) [22-OCT-2020 12:45:12:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:45:12:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:45:12:750] nsbasic_bsd: 00 0B 00 00 0C 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:12:750] nsbasic_bsd: 01 00 02                 |...     |
+(5236) [22-OCT-2020 12:45:12:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:45:13:750] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:45:13:750] nttfprd: entry
+(5236) [22-OCT-2020 12:45:13:750] nttfprd: socket 1288 had bytes read=11
+(5236) [22-OCT-2020 12:45:13:750] nttfprd: exit
+(5236) [22-OCT-2020 12:45:13:750] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:45:13:750] nsbasic_brc: 00 0B 00 00 0C 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:13:750] nsbasic_brc: 01 00 02                 |...     |
+(5236) [22-OCT-2020 12:45:13:750] nsbasic_brc: exit: oln=0, dln=1, tot=11, rc=0
+(5236) [22-OCT-2020 12:45:14:750] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:45:14:750] nttfprd: entry
+(5236) [22-OCT-2020 12:45:14:750] nttfprd: socket 1288 had bytes read=67
+(5236) [22-OCT-2020 12:45:14:750] nttfprd: exit
+(5236) [22-OCT-2020 12:45:14:750] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:45:14:750] nsbasic_brc: 00 43 00 00 06 00 00 00  |.C......|
+(5236) [22-OCT-2020 12:45:14:750] nsbasic_brc: 00 00 04 00 02 06 BA 00  |........|
+(5236) [22-OCT-2020 12:45:14:750] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:14:750] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:14:750] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:14:750] nsbasic_brc: 1A 4F 52 41 2D 30 31 37  |.ORA-017|
+(5236) [22-OCT-2020 12:45:14:750] nsbasic_brc: 32 32 3A 20 69 6E 76 61  |22:.inva|
+(5236) [22-OCT-2020 12:45:14:750] nsbasic_brc: 6C 69 64 20 6E 75 6D 62  |lid.numb|
+(5236) [22-OCT-2020 12:45:14:750] nsbasic_brc: 65 72 0A                 |er.     |
+(5236) [22-OCT-2020 12:45:14:750] nsbasic_brc: exit: oln=0, dln=57, tot=67, rc=0
+`,
+			interrupted: true,
+			errorCode:   1722,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getQueriesFromTraceSnippet(tt.trc)
+			if err != nil {
+				t.Errorf("Error returned error = %v", err)
+				return
+			}
+			if len(got) != 1 {
+				t.Errorf("Number of queries = %d, want 1", len(got))
+				return
+			}
+			q := got[0]
+			if q.Cancelled != tt.cancelled || q.Interrupted != tt.interrupted {
+				t.Errorf("Cancelled = %t, Interrupted = %t, want %t, %t", q.Cancelled, q.Interrupted, tt.cancelled, tt.interrupted)
+			}
+			if q.Response == nil || q.Response.ErrorCode != tt.errorCode {
+				t.Errorf("Response = %v, want error %d", q.Response, tt.errorCode)
+			}
+		})
+	}
+}
+
+func Test_arrayInsert(t *testing.T) {
+	tests := []struct {
+		name      string
+		trc       string
+		rowCounts []uint64
+	}{
+		{
+			name: "rows processed by each iteration",
+			trc: `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=169.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=169
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 A9 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 03 5E 16 02 80 29  |...^...)|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 02  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3F  |.......?|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 49 4E 53 45 52 54 20 49  |INSERT.I|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 4E 54 4F 20 65 66 6C 6F  |NTO.eflo|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 77 5F 70 61 72 61 6D 73  |w_params|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 20 28 70 61 72 61 6D 5F  |.(param_|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 69 64 2C 20 70 61 72 61  |id,.para|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 6D 5F 6E 61 6D 65 29 20  |m_name).|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 56 41 4C 55 45 53 20 28  |VALUES.(|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 2C 20 3A 32 29 01  |:1,.:2).|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 03 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 02  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 01 16 00 01 10  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00 60 00 00  |.....` + "`" + `..|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 14 00 01 10 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 02 03 69 01 00 07 02 C1  |..i.....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 02 01 61 07 02 C1 03 04  |..a.....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 69                       |i       |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=19.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=19
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 13 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 74 27 73 07 02 C1  |..t's...|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 04 01 63                 |..c     |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: socket 1288 had bytes read=60
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 3C 00 00 06 00 00 00  |.<......|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 08 01 03 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 01 03 01 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 01 01 01 04 01 03 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 01 05 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 09 00 01 17              |....    |
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: exit: oln=0, dln=50, tot=60, rc=0
+`,
+			rowCounts: []uint64{1, 1, 1},
+		},
+		{
+			name: "without rows processed by each iteration",
+			trc: `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=169.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=169
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 A9 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 03 5E 16 02 80 29  |...^...)|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 02  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3F  |.......?|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 49 4E 53 45 52 54 20 49  |INSERT.I|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 4E 54 4F 20 65 66 6C 6F  |NTO.eflo|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 77 5F 70 61 72 61 6D 73  |w_params|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 20 28 70 61 72 61 6D 5F  |.(param_|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 69 64 2C 20 70 61 72 61  |id,.para|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 6D 5F 6E 61 6D 65 29 20  |m_name).|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 56 41 4C 55 45 53 20 28  |VALUES.(|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 2C 20 3A 32 29 01  |:1,.:2).|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 03 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 02  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 01 16 00 01 10  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 00 00 00 60 00 00  |.....` + "`" + `..|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 14 00 01 10 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 02 03 69 01 00 07 02 C1  |..i.....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 02 01 61 07 02 C1 03 04  |..a.....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 69                       |i       |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=19.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=19
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 13 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 74 27 73 07 02 C1  |..t's...|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 04 01 63                 |..c     |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: socket 1288 had bytes read=52
+(5236) [22-OCT-2020 12:44:14:751] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 34 00 00 06 00 00 00  |.4......|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 08 01 03 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 04 01 03 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 01 05 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: 09 00 01 17              |....    |
+(5236) [22-OCT-2020 12:44:14:751] nsbasic_brc: exit: oln=0, dln=42, tot=52, rc=0
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getQueriesFromTraceSnippet(tt.trc)
+			if err != nil {
+				t.Errorf("Error returned error = %v", err)
+				return
+			}
+			if len(got) != 1 || len(got[0].BindRows) != 3 {
+				t.Errorf("Number of queries = %d, want 1 with 3 rows of values", len(got))
+				return
+			}
+			q := got[0]
+			values := []string{}
+			for _, row := range q.BindRows {
+				for _, v := range row {
+					values = append(values, v.quoted())
+				}
+			}
+			if want := []string{"1", "'a'", "2", "'it''s'", "3", "'c'"}; !reflect.DeepEqual(values, want) {
+				t.Errorf("Values = %v, want %v", values, want)
+			}
+			if q.Response == nil || q.Response.RowCount != 3 || !reflect.DeepEqual(q.Response.RowCounts, tt.rowCounts) {
+				t.Errorf("Response = %+v, want 3 rows processed and row counts %v", q.Response, tt.rowCounts)
+			}
+			if s := q.String(); !strings.Contains(s, "  batch of 3 rows, first one:\n  :1 = 1\n  :2 = 'a'\n") {
+				t.Errorf("String() = %s", s)
+			}
+			if s := q.Batch(2); !strings.HasSuffix(s, "  ... 1 more row(s)\n") || !strings.Contains(s, "'it''s'") {
+				t.Errorf("Batch() = %s", s)
+			}
+		})
+	}
+}
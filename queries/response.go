@@ -0,0 +1,181 @@
+package queries
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/simulot/oracle_trc/packet"
+	"github.com/simulot/oracle_trc/trc"
+)
+
+// Response hold what the server answered to a query
+type Response struct {
+	Packets      []*trc.Packet               // nsbasic_brc packets received after the query on the same socket
+	CursorId     uint32                      // Cursor used by the server for the query
+	RowCount     uint32                      // Rows processed or fetched by the call
+	ErrorCode    uint32                      // ORA error code, 0 when the call succeeded
+	ErrorPos     uint32                      // Position of the error in the statement
+	ErrorMessage string                      // Error message as sent by the server
+	Rows         [][]*ParameterInfo          // Rows of the result set, values follow the columns
+	RowData      [][]byte                    // Row data that can't be decoded without the columns description
+	RowCounts    []uint64                    // Rows processed by each iteration of array DML, when sent by the server
+	Directions   []byte                      // Direction of each bind, when the server sent the IO vector
+	OutValues    [][][]byte                  // Values sent back for each output bind: one value, or one per row for RETURNING INTO
+	AQMessageId  []byte                      // Id given to the message by an AQ enqueue
+	Dequeued     *packet.AQMessageProperties // Properties of the message got by an AQ dequeue, nil when none
+	XAState      string                      // State of the transaction branch given by the server for an XA call
+}
+
+// String gives a one line summary of the response
+func (r Response) String() string {
+	if err := r.Err(); err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("%d row(s)", r.RowCount)
+}
+
+// Err gives the error sent by the server, nil when the call succeeded
+func (r Response) Err() *OracleError {
+	if r.ErrorCode == 0 {
+		return nil
+	}
+	e := &OracleError{
+		Code:     r.ErrorCode,
+		Position: r.ErrorPos,
+		CursorId: r.CursorId,
+		RowCount: r.RowCount,
+		Message:  strings.TrimSpace(r.ErrorMessage),
+	}
+	if e.Message == "" {
+		e.Message = packet.ErrorMessage(int(e.Code))
+	}
+	return e
+}
+
+// OracleError is an error raised by the server for a call
+type OracleError struct {
+	Code     uint32 // ORA error code
+	Position uint32 // Position of the error in the statement
+	CursorId uint32 // Cursor of the call
+	RowCount uint32 // Rows processed before the error
+	Message  string // Message sent by the server, taken from the messages catalog otherwise
+}
+
+// Error gives the message in its usual form, like ORA-00942: table or view does not exist
+func (e *OracleError) Error() string {
+	switch {
+	case strings.HasPrefix(e.Message, "ORA-"):
+		return e.Message
+	case e.Message == "":
+		return fmt.Sprintf("ORA-%05d", e.Code)
+	}
+	return fmt.Sprintf("ORA-%05d: %s", e.Code, e.Message)
+}
+
+// addPacket append a received packet to the response
+func (r *Response) addPacket(pk *trc.Packet) {
+	r.Packets = append(r.Packets, pk)
+}
+
+// decode walks the TTC messages of the response, rows are typed by the columns description.
+// Packets are joined, a message may span several packets.
+// Decoding stops at the first unknown message or error, what is already decoded is kept.
+// The description sent in the response is returned, the given one otherwise.
+func (r *Response) decode(ctx packet.TTCContext, d *Describe) *Describe {
+	if len(ctx.Columns) == 0 {
+		ctx.Columns = d.packetColumns()
+	}
+	msgs, _ := ctx.ReadMessages(r.payload())
+	if ctx.RowCounts && !hasSummary(msgs) {
+		// The client didn't ask for the rows processed by each iteration
+		ctx.RowCounts = false
+		msgs, _ = ctx.ReadMessages(r.payload())
+	}
+	outBinds := false
+	for _, m := range msgs {
+		switch m := m.(type) {
+		case packet.IOVector:
+			r.Directions = m.Directions
+			outBinds = true
+		case packet.ReturnedValues:
+			r.OutValues = m.Values
+		case packet.ReturnParameters:
+			r.RowCounts = m.RowCounts
+		case packet.AQEnqueueParameters:
+			r.AQMessageId = m.MessageId
+		case packet.AQDequeueParameters:
+			if m.Found {
+				r.Dequeued = &m.Properties
+			}
+		case packet.TxnChangeStateParameters:
+			r.XAState = packet.TxnStateString(m.State)
+		case packet.Summary:
+			r.RowCount = m.RowCount
+			r.ErrorCode = m.ErrorCode
+			r.CursorId = m.CursorId
+			r.ErrorPos = m.ErrorPos
+			r.ErrorMessage = m.ErrorMessage
+		case packet.Describe:
+			d = newDescribe(m)
+		case packet.Row:
+			if outBinds {
+				// Values of the output binds, not a row of a result set
+				r.OutValues = nil
+				for _, v := range m.Values {
+					r.OutValues = append(r.OutValues, [][]byte{v})
+				}
+				outBinds = false
+				continue
+			}
+			r.Rows = append(r.Rows, row(m, d))
+		case packet.RowData:
+			r.RowData = append(r.RowData, m.Data)
+		}
+	}
+	return d
+}
+
+// hasSummary checks if the end of call summary is among the messages
+func hasSummary(msgs []packet.TTCMessage) bool {
+	for _, m := range msgs {
+		if m.Code() == packet.TTIOER {
+			return true
+		}
+	}
+	return false
+}
+
+// payload joins the data of the response's packets behind the header of the first one
+func (r *Response) payload() []byte {
+	return joinPackets(r.Packets)
+}
+
+// joinPackets joins the data of the data packets behind the header of the first one
+func joinPackets(pks []*trc.Packet) []byte {
+	var b []byte
+	for _, pk := range pks {
+		if len(pk.Payload) <= 10 || packet.PacketType(pk.Payload[4]) != packet.Data {
+			continue
+		}
+		if b == nil {
+			b = append(b, pk.Payload[:10]...)
+		}
+		b = append(b, pk.Payload[10:]...)
+	}
+	return b
+}
+
+// row gives the row's values typed by the columns
+func row(m packet.Row, d *Describe) []*ParameterInfo {
+	row := make([]*ParameterInfo, len(m.Values))
+	for i, v := range m.Values {
+		p := &ParameterInfo{}
+		if d != nil && i < len(d.Columns) {
+			*p = *d.Columns[i]
+		}
+		p.Value = v
+		p.IsNull = v == nil
+		row[i] = p
+	}
+	return row
+}
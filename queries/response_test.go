@@ -0,0 +1,577 @@
+package queries
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_getResponseFromTraceSnippet(t *testing.T) {
+	type args struct {
+		trc string
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Response
+	}{
+		{
+			name: "select with table not found error",
+			args: args{
+				trc: `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=82
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 52 00 00 06 00 00 00  |.R......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 04 00 02 03 AE 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 02 01 0E 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 28 4F 52 41 2D 30 30  |.(ORA-00|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 39 34 32 3A 20 74 61 62  |942:.tab|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 6C 65 20 6F 72 20 76 69  |le.or.vi|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 65 77 20 64 6F 65 73 20  |ew.does.|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 6E 6F 74 20 65 78 69 73  |not.exis|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 74 0A                    |t.      |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=72, tot=82, rc=0
+`,
+			},
+			want: &Response{
+				CursorId:     2,
+				RowCount:     0,
+				ErrorCode:    942,
+				ErrorPos:     14,
+				ErrorMessage: "ORA-00942: table or view does not exist\n",
+			},
+		},
+		{
+			name: "successful call with return parameters and status",
+			args: args{
+				trc: `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=51
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 33 00 00 06 00 00 00  |.3......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 08 01 03 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 04 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 09 00 00                 |...     |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=41, tot=51, rc=0
+`,
+			},
+			want: &Response{
+				CursorId: 2,
+				RowCount: 1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := getQueryFromTraceSnippet(tt.args.trc)
+			if err != nil {
+				t.Errorf("Error returned error = %v", err)
+				return
+			}
+			if q == nil {
+				t.Errorf("Query not found")
+				return
+			}
+			got := q.Response
+			if got == nil {
+				t.Errorf("Response not found")
+				return
+			}
+			if len(got.Packets) != 1 {
+				t.Errorf("Response packets = %d, want 1", len(got.Packets))
+			}
+			if got.CursorId != tt.want.CursorId || got.RowCount != tt.want.RowCount ||
+				got.ErrorCode != tt.want.ErrorCode || got.ErrorPos != tt.want.ErrorPos ||
+				got.ErrorMessage != tt.want.ErrorMessage {
+				t.Errorf("Response = \n%#v,\n want \n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_callError(t *testing.T) {
+	tests := []struct {
+		name  string
+		exeOp uint32
+		r     Response
+		want  string
+	}{
+		{
+			name: "success",
+			r:    Response{RowCount: 1},
+			want: "",
+		},
+		{
+			name: "message sent by the server",
+			r:    Response{ErrorCode: 1400, CursorId: 3, ErrorMessage: "ORA-01400: cannot insert NULL into (\"SCOTT\".\"EMP\".\"EMPNO\")\n"},
+			want: "ORA-01400: cannot insert NULL into (\"SCOTT\".\"EMP\".\"EMPNO\")",
+		},
+		{
+			name: "code only, message from the catalog",
+			r:    Response{ErrorCode: 942},
+			want: "ORA-00942: table or view does not exist",
+		},
+		{
+			name: "code only, unknown message",
+			r:    Response{ErrorCode: 20001},
+			want: "ORA-20001",
+		},
+		{
+			name:  "end of fetch",
+			exeOp: exeOpExecute | exeOpFetch,
+			r:     Response{ErrorCode: 1403, RowCount: 12, ErrorMessage: "ORA-01403: no data found\n"},
+			want:  "",
+		},
+		{
+			name:  "no data found without fetch",
+			exeOp: exeOpExecute,
+			r:     Response{ErrorCode: 1403, ErrorMessage: "ORA-01403: no data found\n"},
+			want:  "ORA-01403: no data found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Query{ExeOp: tt.exeOp, Response: &tt.r}
+			err := q.callError()
+			got := ""
+			if err != nil {
+				got = err.Error()
+				if err.CursorId != tt.r.CursorId || err.Code != tt.r.ErrorCode {
+					t.Errorf("callError() = %#v", err)
+				}
+			}
+			if got != tt.want {
+				t.Errorf("callError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resultSet(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=116
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 74 00 00 06 00 00 00  |.t......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 10 00 01 3B 01 03  |.....;..|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 02 00 0A 00 01 16 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 02 01 02 02 49 44 00 00  |....ID..|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 01 00 00 00 01 1E  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 1E 00 01 04 01 04 04  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 4E 41 4D 45 00 00 00 00  |NAME....|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 0C 00 00 00 01 07 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 01 00 00 01 07  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 07 07 43 52 45 41 54  |...CREAT|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 45 44 00 00 00 00 00 00  |ED......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 06 01 01 03  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 01 00              |....    |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=106, tot=116, rc=0
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: socket 1288 had bytes read=117
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 75 00 00 06 00 00 00  |.u......|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 00 00 07 02 C1 2B  |.......+|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 05 53 43 4F 54 54 07 78  |.SCOTT.x|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 78 0A 16 0D 2D 0F 15 01  |x...-...|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 02 05 07 02 C1 08 07 78  |.......x|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 78 0A 16 0D 2D 0F 07 02  |x...-...|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: C1 09 04 4B 49 4E 47 00  |...KING.|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 04 01 03 02 05 7B 00 00  |.....{..|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 01 02 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 00 00 00 00 00 19  |........|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 4F 52 41 2D 30 31 34 30  |ORA-0140|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 33 3A 20 6E 6F 20 64 61  |3:.no.da|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 74 61 20 66 6F 75 6E 64  |ta.found|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 0A 09 00 01 05           |.....   |
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: exit: oln=0, dln=107, tot=117, rc=0
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	if len(got) != 1 || got[0].Response == nil {
+		t.Errorf("Number of queries = %d, want 1 with its response", len(got))
+		return
+	}
+	q := got[0]
+	if q.Err != nil || !strings.HasSuffix(q.String(), "  => 3 row(s)\n") {
+		t.Errorf("Err = %v, String() = %s", q.Err, q.String())
+	}
+	want := "" +
+		"  ID | NAME  | CREATED\n" +
+		"  ---+-------+---------------------\n" +
+		"  42 | SCOTT | 2020-10-22T12:44:14Z\n" +
+		"  7  | SCOTT | 2020-10-22T12:44:14Z\n" +
+		"  ... 1 more row(s)\n"
+	if table := q.Table(2); table != want {
+		t.Errorf("Table() = \n%s, want \n%s", table, want)
+	}
+	wantDescribe := "" +
+		"  ID      NUMBER(10) NOT NULL\n" +
+		"  NAME    VARCHAR2(30)\n" +
+		"  CREATED DATE\n"
+	if q.Describe == nil || q.Describe.String() != wantDescribe {
+		t.Errorf("Describe = %v, want \n%s", q.Describe, wantDescribe)
+	}
+	if last := q.Response.Rows[2]; last[1].String() != "KING" || !last[2].IsNull {
+		t.Errorf("Last row = %v, %v", last[1], last[2])
+	}
+}
+
+func Test_fetch(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=116
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 74 00 00 06 00 00 00  |.t......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 10 00 01 3B 01 03  |.....;..|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 02 00 0A 00 01 16 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 02 01 02 02 49 44 00 00  |....ID..|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 01 00 00 00 01 1E  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 1E 00 01 04 01 04 04  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 4E 41 4D 45 00 00 00 00  |NAME....|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 0C 00 00 00 01 07 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 01 00 00 01 07  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 07 07 43 52 45 41 54  |...CREAT|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 45 44 00 00 00 00 00 00  |ED......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 06 01 01 03  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 01 00              |....    |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=106, tot=116, rc=0
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=63
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 3F 00 00 06 00 00 00  |.?......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 07 02 C1 2B  |.......+|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 05 53 43 4F 54 54 07 78  |.SCOTT.x|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 78 0A 16 0D 2D 0F 04 01  |x...-...|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 00 00 00 01 02 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 09 00 01 05     |....... |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=53, tot=63, rc=0
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: tot=0, plen=17.
+(5236) [22-OCT-2020 12:44:14:760] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:760] nttfpwr: socket 1288 had bytes written=17
+(5236) [22-OCT-2020 12:44:14:760] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: 00 11 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: 00 00 03 05 17 01 02 01  |........|
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: 0A                       |.       |
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:761] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:761] nttfprd: socket 1288 had bytes read=109
+(5236) [22-OCT-2020 12:44:14:761] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 00 6D 00 00 06 00 00 00  |.m......|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 00 00 06 01 01 03 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 01 00 00 00 07 02 C1 08  |........|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 05 53 43 4F 54 54 07 78  |.SCOTT.x|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 78 0A 16 0D 2D 0F 07 02  |x...-...|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: C1 09 04 4B 49 4E 47 00  |...KING.|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 04 01 03 02 05 7B 00 00  |.....{..|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 01 02 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 00 00 00 00 00 00 00 19  |........|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 4F 52 41 2D 30 31 34 30  |ORA-0140|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 33 3A 20 6E 6F 20 64 61  |3:.no.da|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 74 61 20 66 6F 75 6E 64  |ta.found|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 0A 09 00 01 06           |.....   |
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: exit: oln=0, dln=99, tot=109, rc=0
+
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	if len(got) != 1 || got[0].Response == nil {
+		t.Errorf("Number of queries = %d, want 1 with its response", len(got))
+		return
+	}
+	q := got[0]
+	if q.Fetches != 2 || q.RowsFetched != 3 || q.AverageFetch() != 1.5 {
+		t.Errorf("Fetches = %d, RowsFetched = %d, AverageFetch() = %v, want 2, 3, 1.5", q.Fetches, q.RowsFetched, q.AverageFetch())
+	}
+	if q.Err != nil || !strings.HasSuffix(q.String(), "  => 3 row(s) in 2 fetches, 1.5 rows per fetch\n") {
+		t.Errorf("Err = %v, String() = %s", q.Err, q.String())
+	}
+	if len(q.Response.Rows) != 3 || q.Response.Rows[2][1].String() != "KING" {
+		t.Errorf("Rows = %v, want 3 rows ending with KING", q.Response.Rows)
+	}
+}
+
+func Test_fetchWithoutPrefetch(t *testing.T) {
+	// The execution has no fetch option, all the rows come with OFETCH calls
+	trc := `(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 02 03 5E 16 02 80 29  |...^...)|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=116
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 74 00 00 06 00 00 00  |.t......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 10 00 01 3B 01 03  |.....;..|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 02 00 0A 00 01 16 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 02 01 02 02 49 44 00 00  |....ID..|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 01 00 00 00 01 1E  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 1E 00 01 04 01 04 04  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 4E 41 4D 45 00 00 00 00  |NAME....|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 0C 00 00 00 01 07 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 01 00 00 01 07  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 07 07 43 52 45 41 54  |...CREAT|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 45 44 00 00 00 00 00 00  |ED......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 06 01 01 03  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 01 00              |....    |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=106, tot=116, rc=0
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=44
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 2C 00 00 06 00 00 00  |.,......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 04 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 09 00 01 05              |....    |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=34, tot=44, rc=0
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: tot=0, plen=17.
+(5236) [22-OCT-2020 12:44:14:760] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:760] nttfpwr: socket 1288 had bytes written=17
+(5236) [22-OCT-2020 12:44:14:760] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: 00 11 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: 00 00 03 05 17 01 02 01  |........|
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: 0A                       |.       |
+(5236) [22-OCT-2020 12:44:14:760] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:761] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:761] nttfprd: socket 1288 had bytes read=71
+(5236) [22-OCT-2020 12:44:14:761] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 00 47 00 00 06 00 00 00  |.G......|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 00 00 06 01 01 03 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 01 00 00 00 07 02 C1 2B  |.......+|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 05 53 43 4F 54 54 07 78  |.SCOTT.x|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 78 0A 16 0D 2D 0F 04 01  |x...-...|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 01 00 00 00 01 02 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: 00 00 00 09 00 01 05     |....... |
+(5236) [22-OCT-2020 12:44:14:761] nsbasic_brc: exit: oln=0, dln=61, tot=71, rc=0
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: tot=0, plen=17.
+(5236) [22-OCT-2020 12:44:14:770] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:770] nttfpwr: socket 1288 had bytes written=17
+(5236) [22-OCT-2020 12:44:14:770] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: 00 11 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: 00 00 03 05 18 01 02 01  |........|
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: 0A                       |.       |
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:771] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:771] nttfprd: socket 1288 had bytes read=109
+(5236) [22-OCT-2020 12:44:14:771] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 00 6D 00 00 06 00 00 00  |.m......|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 00 00 06 01 01 03 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 01 00 00 00 07 02 C1 08  |........|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 05 53 43 4F 54 54 07 78  |.SCOTT.x|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 78 0A 16 0D 2D 0F 07 02  |x...-...|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: C1 09 04 4B 49 4E 47 00  |...KING.|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 04 01 03 02 05 7B 00 00  |.....{..|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 01 02 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 00 00 00 00 00 00 00 19  |........|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 4F 52 41 2D 30 31 34 30  |ORA-0140|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 33 3A 20 6E 6F 20 64 61  |3:.no.da|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 74 61 20 66 6F 75 6E 64  |ta.found|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 0A 09 00 01 06           |.....   |
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: exit: oln=0, dln=99, tot=109, rc=0
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	if len(got) != 1 || got[0].Response == nil {
+		t.Errorf("Number of queries = %d, want 1 with its response", len(got))
+		return
+	}
+	q := got[0]
+	if q.Fetches != 2 || q.RowsFetched != 3 {
+		t.Errorf("Fetches = %d, RowsFetched = %d, want 2, 3", q.Fetches, q.RowsFetched)
+	}
+	if q.Err != nil || !strings.HasSuffix(q.String(), "  => 3 row(s) in 2 fetches, 1.5 rows per fetch\n") {
+		t.Errorf("Err = %v, String() = %s", q.Err, q.String())
+	}
+	if len(q.Response.Rows) != 3 || q.Response.Rows[2][1].String() != "KING" {
+		t.Errorf("Rows = %v, want 3 rows ending with KING", q.Response.Rows)
+	}
+}
@@ -0,0 +1,364 @@
+package queries
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/simulot/oracle_trc/packet"
+	"github.com/simulot/oracle_trc/trc"
+)
+
+// Session groups packets and queries exchanged on a socket during a database connection
+type Session struct {
+	Id                int            // Session number, in order of appearance in the trace file
+	Name              string         // Trace file name
+	Socket            int            // Socket
+	Pid               int            // Client PID
+	Client            string         // Client name
+	Start             []byte         // Time stamp of the first packet as written in trc file
+	End               []byte         // Time stamp of the last packet or of the socket teardown
+	ConnectDescriptor string         // Connect data sent by the client
+	Version           uint16         // Protocol version negotiated with the Accept packet
+	SDU               uint32         // Session data unit negotiated with the Accept packet
+	TDU               uint32         // Transport data unit negotiated with the Accept packet
+	ClientPlatform    string         // Client's platform sent in the protocol negotiation
+	ClientVersions    []uint8        // TTC protocol versions accepted by the client
+	ServerPlatform    string         // Server's platform sent in the protocol negotiation
+	ProtocolVersion   uint8          // TTC protocol version chosen by the server
+	Charset           uint16         // Database character set
+	NCharset          uint16         // Database national character set
+	ServerFlags       uint8          // Server flags sent in the protocol negotiation
+	CompileTimeCaps   []byte         // Server compile time capabilities
+	RuntimeCaps       []byte         // Server runtime capabilities
+	ServerBanner      string         // Server's version banner, answer to the OVERSION call
+	ServerVersion     string         // Server's version number, like 11.2.0.4.0
+	ClientCompileCaps []byte         // Client compile time capabilities sent in the data types negotiation
+	ClientRuntimeCaps []byte         // Client runtime capabilities sent in the data types negotiation
+	DataTypes         TypeTable      // Negotiated representation of data types
+	Login             packet.Login   // Database user and client information sent during the authentication
+	LoginErr          *OracleError   // Error raised by the authentication, nil when it succeeded or isn't in the trace
+	Closed            bool           // The socket teardown is in the trace
+	Packets           []*trc.Packet  // Session's packets in trace order
+	Queries           []*Query       // Session's queries in trace order
+	Transactions      []*Transaction // Session's transactions in trace order
+	EndToEnd          EndToEnd       // End-to-end attributes in effect, set by the client's piggybacks
+	XID               *packet.XID    // Transaction branch the session is attached to, nil outside xa_start and xa_end
+
+	connected bool                // Connect / Accept exchange done, or session opened in the middle of the exchanges
+	call      packet.FunctionCode // Last function called by the client
+	piggyback []packet.TTCMessage // Piggybacks sent with the last client's call
+	pending   *Query              // Query waiting for its response
+	cursors   map[uint32]*Query   // Parsed queries per cursor id
+	open      map[uint32]*Query   // Queries whose rows are still being fetched, per cursor id
+	fetch     *fetch              // Fetch call waiting for its response
+	tx        *Transaction        // Transaction not ended yet
+	lobs      map[string]*Lob     // LOBs per locator
+	lob       *lobCall            // LOB call waiting for its response
+	reading   *Query              // Query whose LOBs may be read by the next calls
+	sending   bool                // The last data packet on the socket was sent by the client
+	continued bool                // The client's packet continues the call of the previous one
+	request   []*trc.Packet       // Packets of the client's call, parsed when the response comes
+	layout    uint8               // TTC version of the OALL8 layout detected when the negotiation isn't traced
+	hasLayout bool                // The OALL8 layout is detected
+}
+
+// newSession opens a session for the socket of the packet
+func newSession(id int, pk *trc.Packet) *Session {
+	return &Session{
+		Id:      id,
+		Name:    pk.Name,
+		Socket:  pk.Socket,
+		Pid:     pk.Pid,
+		Client:  pk.Client,
+		Start:   pk.TS,
+		cursors: make(map[uint32]*Query),
+		open:    make(map[uint32]*Query),
+		lobs:    make(map[string]*Lob),
+	}
+}
+
+// String gives session's context on one line
+func (s Session) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(s.Name)
+	sb.WriteString(fmt.Sprintf(" Session #%d, ", s.Id))
+	sb.WriteString(s.Client)
+	sb.WriteString(fmt.Sprintf("(%d),", s.Pid))
+	sb.WriteString(fmt.Sprintf(" Socket(%d), ", s.Socket))
+	if s.Login.User != "" {
+		sb.WriteString(fmt.Sprintf("User(%s), ", s.Login.User))
+	}
+	sb.Write(s.Start)
+	sb.WriteString(" - ")
+	sb.Write(s.End)
+	if !s.Closed {
+		sb.WriteString(" (not closed)")
+	}
+	if s.ServerBanner != "" {
+		sb.WriteString(", ")
+		sb.WriteString(s.ServerBanner)
+	} else if s.ServerPlatform != "" {
+		sb.WriteString(", server ")
+		sb.WriteString(s.ServerPlatform)
+	}
+	if s.Charset != 0 {
+		sb.WriteString(", ")
+		sb.WriteString(packet.CharsetName(s.Charset))
+		sb.WriteString("/")
+		sb.WriteString(packet.CharsetName(s.NCharset))
+	}
+	if s.ConnectDescriptor != "" {
+		sb.WriteString(", ")
+		sb.WriteString(s.ConnectDescriptor)
+	}
+	return sb.String()
+}
+
+// addPacket appends the packet to the session and follows the connection's lifecycle
+func (s *Session) addPacket(pk *trc.Packet) {
+	s.Packets = append(s.Packets, pk)
+	sending := s.sending
+	s.sending, s.continued = false, false
+	if len(pk.TS) > 0 {
+		s.End = pk.TS
+	}
+	if s.Client == "" {
+		s.Client = pk.Client
+	}
+	if len(pk.Payload) < 8 {
+		return
+	}
+	switch packet.PacketType(pk.Payload[4]) {
+	case packet.Connect:
+		if s.ConnectDescriptor == "" {
+			s.ConnectDescriptor = packet.ReadConnectPacket(pk.Payload).ConnectData
+		}
+	case packet.Accept:
+		ap := packet.ReadAcceptPacket(pk.Payload)
+		s.Version, s.SDU, s.TDU = ap.Version, ap.SDU, ap.TDU
+		s.connected = true
+	case packet.Data:
+		s.connected = true
+		if sentByClient(pk) {
+			// A call larger than the SDU is sent in several packets in a row
+			s.sending = true
+			s.continued = sending
+			if s.continued {
+				return
+			}
+			s.clientCall(pk)
+		}
+		s.handshake(pk)
+	}
+}
+
+// handshake follows the protocol negotiation, the server version call and the authentication
+func (s *Session) handshake(pk *trc.Packet) {
+	ctx := packet.TTCContext{
+		FromServer:      !sentByClient(pk),
+		Function:        s.call,
+		CompileTimeCaps: s.ClientCompileCaps,
+		RuntimeCaps:     s.ClientRuntimeCaps,
+	}
+	if len(pk.Payload) <= 10 {
+		return
+	}
+	switch packet.TTCCode(pk.Payload[10]) {
+	case packet.TTIPRO, packet.TTIDTY:
+	default:
+		switch s.call {
+		case packet.OVERSION, packet.OSESSKEY, packet.OAUTH:
+		default:
+			return
+		}
+	}
+	msgs, _ := ctx.ReadMessages(pk.Payload)
+	for _, m := range msgs {
+		switch m := m.(type) {
+		case packet.ProtocolNegotiation:
+			if !m.FromServer {
+				s.ClientPlatform = m.Banner
+				s.ClientVersions = m.Versions
+				continue
+			}
+			s.ServerPlatform = m.Banner
+			s.ProtocolVersion = m.Version()
+			s.Charset, s.NCharset = m.Charset, m.NCharset
+			s.ServerFlags = m.Flags
+			s.CompileTimeCaps, s.RuntimeCaps = m.CompileTimeCaps, m.RuntimeCaps
+		case packet.DataTypeNegotiation:
+			if !m.FromServer {
+				s.ClientCompileCaps, s.ClientRuntimeCaps = m.CompileTimeCaps, m.RuntimeCaps
+			}
+			if m.FromServer || len(s.DataTypes) == 0 {
+				// The server's table is the accepted one
+				s.DataTypes = make(TypeTable, len(m.Types))
+				for _, t := range m.Types {
+					s.DataTypes[OracleType(t.Type)] = t
+				}
+			}
+		case packet.ServerVersion:
+			s.ServerBanner = m.Banner
+			s.ServerVersion = m.Version()
+		case packet.AuthCall, packet.AuthParameters:
+			s.Login.Add(m)
+		case packet.Summary:
+			if s.call == packet.OSESSKEY || s.call == packet.OAUTH {
+				s.LoginErr = Response{ErrorCode: m.ErrorCode, ErrorMessage: m.ErrorMessage}.Err()
+			}
+		}
+	}
+}
+
+// clientCall follows the function called by the client and the piggybacks sent in front of it
+func (s *Session) clientCall(pk *trc.Packet) {
+	s.call = 0
+	s.piggyback = nil
+	msgs, _ := s.requestContext().ReadMessages(pk.Payload)
+	for _, m := range msgs {
+		if call, ok := m.(packet.Caller); ok {
+			s.call = call.Call().Function
+			return
+		}
+		if m.Code() == packet.TTIPFN {
+			s.piggyback = append(s.piggyback, m)
+		}
+	}
+}
+
+// closeCursors forgets cursors closed by the client's piggybacks, their ids are returned.
+// It's called once the response of the previous call has been handled, its cursor may be the closed one.
+func (s *Session) closeCursors() []uint32 {
+	var ids []uint32
+	for _, m := range s.piggyback {
+		if m, ok := m.(packet.CursorClose); ok {
+			for _, id := range m.Ids {
+				delete(s.cursors, id)
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// isConnect checks if the packet is a connection request that opens a new session.
+func isConnect(pk *trc.Packet) bool {
+	return len(pk.Payload) > 8 && packet.PacketType(pk.Payload[4]) == packet.Connect
+}
+
+// connectionEvent describes Refuse and Redirect packets, an empty string is returned for other packets
+func connectionEvent(pk *trc.Packet) string {
+	if len(pk.Payload) < 8 {
+		return ""
+	}
+	switch packet.PacketType(pk.Payload[4]) {
+	case packet.Refuse:
+		return packet.ReadRefusePacket(pk.Payload).Event()
+	case packet.Redirect:
+		return packet.ReadRedirectPacket(pk.Payload).Event()
+	}
+	return ""
+}
+
+// closingEvent describes a session closed without statements, like a refused login
+func (s *Session) closingEvent() string {
+	switch {
+	case s.LoginErr != nil && s.Login.User != "":
+		return "login refused for " + s.Login.User
+	case s.LoginErr != nil:
+		return "login refused"
+	}
+	return "session without statement"
+}
+
+// mark handles Marker and Attention packets, it returns false for other packets.
+// A break sent by the client cancels the call in flight, a break sent by the server interrupts it.
+func (s *Session) mark(pk *trc.Packet) bool {
+	if len(pk.Payload) < 8 {
+		return false
+	}
+	switch packet.PacketType(pk.Payload[4]) {
+	case packet.Marker, packet.Attention:
+	default:
+		return false
+	}
+	q := s.pending
+	if q == nil {
+		return true
+	}
+	switch packet.ReadMarkerPacket(pk.Payload, pk.Version).Kind {
+	case packet.BreakMarker, packet.InterruptMarker:
+		if sentByClient(pk) {
+			q.Cancelled = true
+		} else if !q.Cancelled {
+			q.Interrupted = true
+		}
+	}
+	return true
+}
+
+// defaultTTCVersion is assumed when the session's negotiation isn't in the trace
+const defaultTTCVersion = 11
+
+// ttcVersion gives the TTC version of the session, the lowest of the client's and the server's ones
+func (s *Session) ttcVersion() uint8 {
+	if v := s.knownTTCVersion(); v > 0 {
+		return v
+	}
+	return defaultTTCVersion
+}
+
+// knownTTCVersion gives the TTC version of the session, 0 when the negotiation isn't in the trace
+func (s *Session) knownTTCVersion() uint8 {
+	v := uint8(0)
+	for _, caps := range [][]byte{s.ClientCompileCaps, s.CompileTimeCaps} {
+		if len(caps) > 7 && (v == 0 || caps[7] < v) {
+			v = caps[7]
+		}
+	}
+	return v
+}
+
+// responseContext gives what the decoding of the server's response to the function depends on
+func (s *Session) responseContext(f packet.FunctionCode) packet.TTCContext {
+	return packet.TTCContext{
+		FromServer:      true,
+		Function:        f,
+		CompileTimeCaps: s.ClientCompileCaps,
+		RuntimeCaps:     s.ClientRuntimeCaps,
+		TTCVersion:      s.ttcVersion(),
+	}
+}
+
+// requestContext gives what the decoding of the client's calls depends on
+func (s *Session) requestContext() packet.TTCContext {
+	return packet.TTCContext{
+		CompileTimeCaps: s.ClientCompileCaps,
+		RuntimeCaps:     s.ClientRuntimeCaps,
+		TTCVersion:      s.ttcVersion(),
+	}
+}
+
+// wireType gives the type used on the wire for values of the given type, according the data types negotiation
+func (s *Session) wireType(t OracleType) OracleType {
+	if r, ok := s.DataTypes[t]; ok && r.ConvType != 0 {
+		return OracleType(r.ConvType)
+	}
+	return t
+}
+
+// sentByClient checks the packet direction
+func sentByClient(pk *trc.Packet) bool {
+	return pk.Typ == "nsbasic_bsd" || pk.Typ == "nspsend"
+}
+
+// registerCursor remembers the statement parsed into the cursor given by the server
+func (s *Session) registerCursor(q *Query) {
+	if q.Reexecuted || q.Query == "" || q.Response.CursorId == 0 {
+		return
+	}
+	if q.CursorId == 0 {
+		q.CursorId = q.Response.CursorId
+	}
+	s.cursors[q.Response.CursorId] = q
+}
@@ -0,0 +1,771 @@
+package queries
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/simulot/oracle_trc/packet"
+)
+
+func Test_sessionLifecycle(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:700] nspsend: entry
+(5236) [22-OCT-2020 12:44:14:700] nspsend: plen=215, type=1
+(5236) [22-OCT-2020 12:44:14:700] nttwr: entry
+(5236) [22-OCT-2020 12:44:14:700] nttwr: socket 1288 had bytes written=215
+(5236) [22-OCT-2020 12:44:14:700] nttwr: exit
+(5236) [22-OCT-2020 12:44:14:700] nspsend: packet dump
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 00 D7 00 00 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 01 3A 01 2C 0C 41 20 00  |.:.,.A..|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: FF FF 7F 08 00 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 00 9D 00 3A 00 00 00 00  |...:....|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 41 41 00 00 00 00 00 00  |AA......|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 00 00 28 44 45 53 43 52  |..(DESCR|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 49 50 54 49 4F 4E 3D 28  |IPTION=(|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 43 4F 4E 4E 45 43 54 5F  |CONNECT_|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 44 41 54 41 3D 28 53 49  |DATA=(SI|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 44 3D 42 53 57 44 30 31  |D=BSWD01|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 29 28 43 49 44 3D 28 50  |)(CID=(P|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 52 4F 47 52 41 4D 3D 43  |ROGRAM=C|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 3A 5C 41 70 70 5C 53 65  |:\App\Se|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 72 76 69 63 65 2E 65 78  |rvice.ex|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 65 29 28 48 4F 53 54 3D  |e)(HOST=|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 41 50 50 53 45 52 56 52  |APPSERVR|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 29 28 55 53 45 52 3D 53  |)(USER=S|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 59 53 54 45 4D 29 29 29  |YSTEM)))|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 28 41 44 44 52 45 53 53  |(ADDRESS|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 3D 28 50 52 4F 54 4F 43  |=(PROTOC|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 4F 4C 3D 54 43 50 29 28  |OL=TCP)(|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 48 4F 53 54 3D 31 30 2E  |HOST=10.|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 33 30 2E 31 39 34 2E 37  |30.194.7|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 37 29 28 50 4F 52 54 3D  |7)(PORT=|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 31 35 32 35 29 29 29     |1525))) |
+(5236) [22-OCT-2020 12:44:14:700] nspsend: normal exit
+(5236) [22-OCT-2020 12:44:14:710] nsprecv: entry
+(5236) [22-OCT-2020 12:44:14:710] nsprecv: reading from transport...
+(5236) [22-OCT-2020 12:44:14:710] nttrd: entry
+(5236) [22-OCT-2020 12:44:14:710] nttrd: socket 1288 had bytes read=32
+(5236) [22-OCT-2020 12:44:14:710] nttrd: exit
+(5236) [22-OCT-2020 12:44:14:710] nsprecv: 32 bytes from transport
+(5236) [22-OCT-2020 12:44:14:710] nsprecv: tlen=32, plen=32, type=2
+(5236) [22-OCT-2020 12:44:14:710] nsprecv: packet dump
+(5236) [22-OCT-2020 12:44:14:710] nsprecv: 00 20 00 00 02 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:710] nsprecv: 01 3A 00 01 20 00 FF FF  |.:......|
+(5236) [22-OCT-2020 12:44:14:710] nsprecv: 01 00 00 00 00 20 41 01  |......A.|
+(5236) [22-OCT-2020 12:44:14:710] nsprecv: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:710] nsprecv: normal exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=51
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 33 00 00 06 00 00 00  |.3......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 08 01 03 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 04 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 09 00 00                 |...     |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=41, tot=51, rc=0
+(5236) [22-OCT-2020 12:44:20:100] nsclose: entry
+(5236) [22-OCT-2020 12:44:20:100] nttdisc: entry
+(5236) [22-OCT-2020 12:44:20:101] nttdisc: Closed socket 1288
+(5236) [22-OCT-2020 12:44:20:101] nttdisc: exit
+(5236) [22-OCT-2020 12:45:01:000] nspsend: entry
+(5236) [22-OCT-2020 12:45:01:000] nspsend: plen=215, type=1
+(5236) [22-OCT-2020 12:45:01:000] nttwr: entry
+(5236) [22-OCT-2020 12:45:01:000] nttwr: socket 1288 had bytes written=215
+(5236) [22-OCT-2020 12:45:01:000] nttwr: exit
+(5236) [22-OCT-2020 12:45:01:000] nspsend: packet dump
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 00 D7 00 00 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 01 3A 01 2C 0C 41 20 00  |.:.,.A..|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: FF FF 7F 08 00 00 00 01  |........|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 00 9D 00 3A 00 00 00 00  |...:....|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 41 41 00 00 00 00 00 00  |AA......|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 00 00 28 44 45 53 43 52  |..(DESCR|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 49 50 54 49 4F 4E 3D 28  |IPTION=(|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 43 4F 4E 4E 45 43 54 5F  |CONNECT_|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 44 41 54 41 3D 28 53 49  |DATA=(SI|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 44 3D 42 53 57 44 30 31  |D=BSWD01|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 29 28 43 49 44 3D 28 50  |)(CID=(P|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 52 4F 47 52 41 4D 3D 43  |ROGRAM=C|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 3A 5C 41 70 70 5C 53 65  |:\App\Se|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 72 76 69 63 65 2E 65 78  |rvice.ex|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 65 29 28 48 4F 53 54 3D  |e)(HOST=|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 41 50 50 53 45 52 56 52  |APPSERVR|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 29 28 55 53 45 52 3D 53  |)(USER=S|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 59 53 54 45 4D 29 29 29  |YSTEM)))|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 28 41 44 44 52 45 53 53  |(ADDRESS|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 3D 28 50 52 4F 54 4F 43  |=(PROTOC|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 4F 4C 3D 54 43 50 29 28  |OL=TCP)(|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 48 4F 53 54 3D 31 30 2E  |HOST=10.|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 33 30 2E 31 39 34 2E 37  |30.194.7|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 37 29 28 50 4F 52 54 3D  |7)(PORT=|
+(5236) [22-OCT-2020 12:45:01:000] nspsend: 31 35 32 35 29 29 29     |1525))) |
+(5236) [22-OCT-2020 12:45:01:000] nspsend: normal exit
+(5236) [22-OCT-2020 12:45:01:010] nsprecv: entry
+(5236) [22-OCT-2020 12:45:01:010] nsprecv: reading from transport...
+(5236) [22-OCT-2020 12:45:01:010] nttrd: entry
+(5236) [22-OCT-2020 12:45:01:010] nttrd: socket 1288 had bytes read=32
+(5236) [22-OCT-2020 12:45:01:010] nttrd: exit
+(5236) [22-OCT-2020 12:45:01:010] nsprecv: 32 bytes from transport
+(5236) [22-OCT-2020 12:45:01:010] nsprecv: tlen=32, plen=32, type=2
+(5236) [22-OCT-2020 12:45:01:010] nsprecv: packet dump
+(5236) [22-OCT-2020 12:45:01:010] nsprecv: 00 20 00 00 02 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:01:010] nsprecv: 01 3A 00 01 20 00 FF FF  |.:......|
+(5236) [22-OCT-2020 12:45:01:010] nsprecv: 01 00 00 00 00 20 41 01  |......A.|
+(5236) [22-OCT-2020 12:45:01:010] nsprecv: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:01:010] nsprecv: normal exit
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:45:01:050] nttfpwr: entry
+(5236) [22-OCT-2020 12:45:01:050] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:45:01:050] nttfpwr: exit
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:45:01:050] nsbasic_bsd: exit (0)
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	if len(got) != 2 {
+		t.Errorf("Number of queries = %d, want 2", len(got))
+		return
+	}
+
+	s1, s2 := got[0].Session, got[1].Session
+	if s1 == nil || s2 == nil || s1 == s2 {
+		t.Errorf("Queries should belong to 2 different sessions, got %v and %v", s1, s2)
+		return
+	}
+
+	wantDescriptor := "(DESCRIPTION=(CONNECT_DATA=(SID=BSWD01)(CID=(PROGRAM=C:\\App\\Service.exe)(HOST=APPSERVR)(USER=SYSTEM)))(ADDRESS=(PROTOCOL=TCP)(HOST=10.30.194.77)(PORT=1525)))"
+	tests := []struct {
+		s       *Session
+		id      int
+		start   string
+		end     string
+		closed  bool
+		packets int
+	}{
+		{s1, 1, "22-OCT-2020 12:44:14:700", "22-OCT-2020 12:44:20:101", true, 5},
+		{s2, 2, "22-OCT-2020 12:45:01:000", "22-OCT-2020 12:45:01:050", false, 3},
+	}
+	for _, tt := range tests {
+		s := tt.s
+		if s.Id != tt.id || s.Socket != 1288 || s.Pid != 5236 {
+			t.Errorf("Session #%d: Id = %d, Socket = %d, Pid = %d", tt.id, s.Id, s.Socket, s.Pid)
+		}
+		if string(s.Start) != tt.start || string(s.End) != tt.end {
+			t.Errorf("Session #%d: Start = %s, End = %s, want %s, %s", tt.id, s.Start, s.End, tt.start, tt.end)
+		}
+		if s.Closed != tt.closed {
+			t.Errorf("Session #%d: Closed = %t, want %t", tt.id, s.Closed, tt.closed)
+		}
+		if len(s.Packets) != tt.packets || len(s.Queries) != 1 {
+			t.Errorf("Session #%d: packets = %d, queries = %d, want %d, 1", tt.id, len(s.Packets), len(s.Queries), tt.packets)
+		}
+		if s.ConnectDescriptor != wantDescriptor {
+			t.Errorf("Session #%d: ConnectDescriptor = %s, want %s", tt.id, s.ConnectDescriptor, wantDescriptor)
+		}
+	}
+}
+
+func Test_connectionRefused(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:700] nspsend: entry
+(5236) [22-OCT-2020 12:44:14:700] nspsend: plen=225, type=1
+(5236) [22-OCT-2020 12:44:14:700] nttwr: entry
+(5236) [22-OCT-2020 12:44:14:700] nttwr: socket 1288 had bytes written=225
+(5236) [22-OCT-2020 12:44:14:700] nttwr: exit
+(5236) [22-OCT-2020 12:44:14:700] nspsend: packet dump
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 00 E1 00 00 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 01 3A 01 2C 0C 41 20 00  |.:.,.A..|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: FF FF 7F 08 00 00 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 00 A7 00 3A 00 00 00 00  |...:....|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 41 41 00 00 00 00 00 00  |AA......|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 00 00 28 44 45 53 43 52  |..(DESCR|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 49 50 54 49 4F 4E 3D 28  |IPTION=(|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 43 4F 4E 4E 45 43 54 5F  |CONNECT_|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 44 41 54 41 3D 28 53 45  |DATA=(SE|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 52 56 49 43 45 5F 4E 41  |RVICE_NA|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 4D 45 3D 55 4E 4B 4E 4F  |ME=UNKNO|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 57 4E 29 28 43 49 44 3D  |WN)(CID=|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 28 50 52 4F 47 52 41 4D  |(PROGRAM|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 3D 43 3A 5C 41 70 70 5C  |=C:\App\|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 53 65 72 76 69 63 65 2E  |Service.|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 65 78 65 29 28 48 4F 53  |exe)(HOS|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 54 3D 41 50 50 53 45 52  |T=APPSER|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 56 52 29 28 55 53 45 52  |VR)(USER|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 3D 53 59 53 54 45 4D 29  |=SYSTEM)|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 29 29 28 41 44 44 52 45  |))(ADDRE|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 53 53 3D 28 50 52 4F 54  |SS=(PROT|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 4F 43 4F 4C 3D 54 43 50  |OCOL=TCP|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 29 28 48 4F 53 54 3D 31  |)(HOST=1|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 30 2E 33 30 2E 31 39 34  |0.30.194|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 2E 37 37 29 28 50 4F 52  |.77)(POR|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 54 3D 31 35 32 35 29 29  |T=1525))|
+(5236) [22-OCT-2020 12:44:14:700] nspsend: 29                       |)       |
+(5236) [22-OCT-2020 12:44:14:700] nspsend: normal exit
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: entry
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: reading from transport...
+(5236) [22-OCT-2020 12:44:14:702] nttrd: entry
+(5236) [22-OCT-2020 12:44:14:702] nttrd: socket 1288 had bytes read=103
+(5236) [22-OCT-2020 12:44:14:702] nttrd: exit
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 103 bytes from transport
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: tlen=103, plen=103, type=4
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: packet dump
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 00 67 00 00 04 00 00 00  |.g......|
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 22 00 00 5B 28 44 45 53  |"..[(DES|
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 43 52 49 50 54 49 4F 4E  |CRIPTION|
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 3D 28 54 4D 50 3D 29 28  |=(TMP=)(|
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 56 53 4E 4E 55 4D 3D 31  |VSNNUM=1|
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 38 36 36 34 37 35 35 32  |86647552|
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 29 28 45 52 52 3D 31 32  |)(ERR=12|
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 35 31 34 29 28 45 52 52  |514)(ERR|
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 4F 52 5F 53 54 41 43 4B  |OR_STACK|
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 3D 28 45 52 52 4F 52 3D  |=(ERROR=|
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 28 43 4F 44 45 3D 31 32  |(CODE=12|
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 35 31 34 29 28 45 4D 46  |514)(EMF|
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: 49 3D 34 29 29 29 29     |I=4)))) |
+(5236) [22-OCT-2020 12:44:14:702] nsprecv: normal exit
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	if len(got) != 1 {
+		t.Errorf("Number of events = %d, want 1", len(got))
+		return
+	}
+	if want := "connection refused: ORA-12514: TNS:listener does not currently know of service requested in connect descriptor"; got[0].Event != want {
+		t.Errorf("Event = %s, want %s", got[0].Event, want)
+	}
+	if got[0].Session == nil || len(got[0].Session.Queries) != 1 {
+		t.Errorf("Event not attached to its session")
+	}
+}
+
+func Test_negotiation(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:720] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:720] nsbasic_bsd: tot=0, plen=37.
+(5236) [22-OCT-2020 12:44:14:720] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:720] nttfpwr: socket 1288 had bytes written=37
+(5236) [22-OCT-2020 12:44:14:720] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:720] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:720] nsbasic_bsd: 00 25 00 00 06 00 00 00  |.%......|
+(5236) [22-OCT-2020 12:44:14:720] nsbasic_bsd: 00 00 01 06 05 04 03 02  |........|
+(5236) [22-OCT-2020 12:44:14:720] nsbasic_bsd: 01 00 49 42 4D 50 43 2F  |..IBMPC/|
+(5236) [22-OCT-2020 12:44:14:720] nsbasic_bsd: 57 49 4E 5F 4E 54 2D 38  |WIN_NT-8|
+(5236) [22-OCT-2020 12:44:14:720] nsbasic_bsd: 2E 31 2E 30 00           |.1.0.   |
+(5236) [22-OCT-2020 12:44:14:720] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:722] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:722] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:722] nttfprd: socket 1288 had bytes read=72
+(5236) [22-OCT-2020 12:44:14:722] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:722] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:722] nsbasic_brc: 00 48 00 00 06 00 00 00  |.H......|
+(5236) [22-OCT-2020 12:44:14:722] nsbasic_brc: 00 00 01 06 00 78 38 36  |.....x86|
+(5236) [22-OCT-2020 12:44:14:722] nsbasic_brc: 5F 36 34 2F 4C 69 6E 75  |_64/Linu|
+(5236) [22-OCT-2020 12:44:14:722] nsbasic_brc: 78 20 32 2E 34 2E 78 78  |x.2.4.xx|
+(5236) [22-OCT-2020 12:44:14:722] nsbasic_brc: 00 69 03 01 00 00 00 0B  |.i......|
+(5236) [22-OCT-2020 12:44:14:722] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:722] nsbasic_brc: 00 07 D0 01 08 08 06 01  |........|
+(5236) [22-OCT-2020 12:44:14:722] nsbasic_brc: 00 00 6A 01 01 06 01 07  |..j.....|
+(5236) [22-OCT-2020 12:44:14:722] nsbasic_brc: 07 02 01 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:722] nsbasic_brc: exit: oln=0, dln=62, tot=72, rc=0
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: tot=0, plen=125.
+(5236) [22-OCT-2020 12:44:14:724] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:724] nttfpwr: socket 1288 had bytes written=125
+(5236) [22-OCT-2020 12:44:14:724] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 00 7D 00 00 06 00 00 00  |.}......|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 00 00 02 69 03 D0 07 01  |...i....|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 2D 06 01 00 00 6A 01 01  |-....j..|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 06 01 01 01 01 01 01 00  |........|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 29 90 03 07 03 00 01 00  |).......|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: EB 01 00 05 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 18 00 00 07 20 02 3A 00  |......:.|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 00 05 00 00 00 08 07 02  |........|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 01 00 00 00 00 00 80 00  |........|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 00 00 3C 3C 3C 80 00 00  |..<<<...|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 00 00 00 00 20 D0 07 00  |........|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 01 00 01 00 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 02 00 02 00 0A 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 44 00 02 00 0A 00 00 00  |D.......|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 60 00 01 00 01 00 00 00  |` + "`" + `.......|
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: 0C 00 00 00 00           |.....   |
+(5236) [22-OCT-2020 12:44:14:724] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:726] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:726] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:726] nttfprd: socket 1288 had bytes read=57
+(5236) [22-OCT-2020 12:44:14:726] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:726] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:726] nsbasic_brc: 00 39 00 00 06 00 00 00  |.9......|
+(5236) [22-OCT-2020 12:44:14:726] nsbasic_brc: 00 00 02 00 00 00 20 00  |........|
+(5236) [22-OCT-2020 12:44:14:726] nsbasic_brc: 01 00 01 00 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:726] nsbasic_brc: 02 00 02 00 0A 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:726] nsbasic_brc: 44 00 02 00 0A 00 00 00  |D.......|
+(5236) [22-OCT-2020 12:44:14:726] nsbasic_brc: 60 00 01 00 01 00 00 00  |` + "`" + `.......|
+(5236) [22-OCT-2020 12:44:14:726] nsbasic_brc: 0C 00 0C 00 0A 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:726] nsbasic_brc: 00                       |.       |
+(5236) [22-OCT-2020 12:44:14:726] nsbasic_brc: exit: oln=0, dln=47, tot=57, rc=0
+(5236) [22-OCT-2020 12:44:14:730] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:730] nsbasic_bsd: tot=0, plen=33.
+(5236) [22-OCT-2020 12:44:14:730] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:730] nttfpwr: socket 1288 had bytes written=33
+(5236) [22-OCT-2020 12:44:14:730] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:730] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:730] nsbasic_bsd: 00 21 00 00 06 00 00 00  |.!......|
+(5236) [22-OCT-2020 12:44:14:730] nsbasic_bsd: 00 00 11 6B 04 02 03 68  |...k...h|
+(5236) [22-OCT-2020 12:44:14:730] nsbasic_bsd: 02 05 D1 01 01 03 3B 05  |......;.|
+(5236) [22-OCT-2020 12:44:14:730] nsbasic_bsd: 01 02 03 00 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:730] nsbasic_bsd: 00                       |.       |
+(5236) [22-OCT-2020 12:44:14:730] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:732] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:732] nttfprd: socket 1288 had bytes read=100
+(5236) [22-OCT-2020 12:44:14:732] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 00 64 00 00 06 00 00 00  |.d......|
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 00 00 08 01 4C 4C 4F 72  |....LLOr|
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 61 63 6C 65 20 44 61 74  |acle.Dat|
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 61 62 61 73 65 20 31 31  |abase.11|
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 67 20 45 6E 74 65 72 70  |g.Enterp|
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 72 69 73 65 20 45 64 69  |rise.Edi|
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 74 69 6F 6E 20 52 65 6C  |tion.Rel|
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 65 61 73 65 20 31 31 2E  |ease.11.|
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 32 2E 30 2E 34 2E 30 20  |2.0.4.0.|
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 2D 20 36 34 62 69 74 20  |-.64bit.|
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 50 72 6F 64 75 63 74 69  |Producti|
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 6F 6E 04 0B 20 04 00 09  |on......|
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: 01 01 01 03              |....    |
+(5236) [22-OCT-2020 12:44:14:732] nsbasic_brc: exit: oln=0, dln=90, tot=100, rc=0
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=51
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 33 00 00 06 00 00 00  |.3......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 08 01 03 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 04 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 09 00 00                 |...     |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=41, tot=51, rc=0
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	if len(got) != 1 || got[0].Session == nil {
+		t.Errorf("Number of queries = %d, want 1", len(got))
+		return
+	}
+	s := got[0].Session
+	if s.ClientPlatform != "IBMPC/WIN_NT-8.1.0" || s.ServerPlatform != "x86_64/Linux 2.4.xx" || s.ProtocolVersion != 6 {
+		t.Errorf("Platforms = %s, %s, protocol version = %d", s.ClientPlatform, s.ServerPlatform, s.ProtocolVersion)
+	}
+	if s.Charset != 873 || s.NCharset != 2000 {
+		t.Errorf("Charsets = %d, %d, want 873, 2000", s.Charset, s.NCharset)
+	}
+	if s.ServerVersion != "11.2.0.4.0" || s.ServerBanner != "Oracle Database 11g Enterprise Edition Release 11.2.0.4.0 - 64bit Production" {
+		t.Errorf("Server version = %s, banner = %s", s.ServerVersion, s.ServerBanner)
+	}
+	if s.wireType(UINT) != NUMBER || s.wireType(DATE) != DATE || len(s.DataTypes) != 5 {
+		t.Errorf("DataTypes = %v", s.DataTypes)
+	}
+	if p := got[0].Params; len(p) != 1 || p[0].WireType != NCHAR || p[0].String() != "LICEXPIRATIONDATE" {
+		t.Errorf("Params = %v", p)
+	}
+	if !strings.HasSuffix(s.String(), "64bit Production, AL32UTF8/AL16UTF16") {
+		t.Errorf("String() = %s", s.String())
+	}
+}
+
+func Test_authentication(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:14:740] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:740] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:14:740] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 00 00 03 76 02 01 01 05  |...v....|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 01 01 01 01 05 01 01 05  |........|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 53 43 4F 54 54 01 0D 0D  |SCOTT...|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 41 55 54 48 5F 54 45 52  |AUTH_TER|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 4D 49 4E 41 4C 01 05 05  |MINAL...|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 50 43 2D 34 32 00 01 0F  |PC-42...|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 0F 41 55 54 48 5F 50 52  |.AUTH_PR|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 4F 47 52 41 4D 5F 4E 4D  |OGRAM_NM|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 01 0B 0B 73 71 6C 70 6C  |...sqlpl|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 75 73 2E 65 78 65 00 01  |us.exe..|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 0C 0C 41 55 54 48 5F 4D  |..AUTH_M|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 41 43 48 49 4E 45 01 0F  |ACHINE..|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 0F 57 4F 52 4B 47 52 4F  |.WORKGRO|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 55 50 5C 50 43 2D 34 32  |UP\PC-42|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 00 01 08 08 41 55 54 48  |....AUTH|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 5F 50 49 44 01 09 09 35  |_PID...5|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 32 33 36 3A 35 32 34 30  |236:5240|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 00 01 08 08 41 55 54 48  |....AUTH|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 5F 53 49 44 01 04 04 6A  |_SID...j|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 64 6F 65 00              |doe.    |
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:742] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:742] nttfprd: socket 1288 had bytes read=79
+(5236) [22-OCT-2020 12:44:14:742] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 00 4F 00 00 06 00 00 00  |.O......|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 00 00 08 01 02 01 0C 0C  |........|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 41 55 54 48 5F 53 45 53  |AUTH_SES|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 53 4B 45 59 01 0C 0C 30  |SKEY...0|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 41 31 42 32 43 33 44 34  |A1B2C3D4|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 45 35 46 01 01 01 0D 0D  |E5F.....|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 41 55 54 48 5F 56 46 52  |AUTH_VFR|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 5F 44 41 54 41 01 08 08  |_DATA...|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 46 30 30 44 46 30 30 44  |F00DF00D|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 02 1B 25 09 00 01 03     |..%.... |
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: exit: oln=0, dln=69, tot=79, rc=0
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: tot=0, plen=220.
+(5236) [22-OCT-2020 12:44:14:744] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:744] nttfpwr: socket 1288 had bytes written=220
+(5236) [22-OCT-2020 12:44:14:744] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 00 DC 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 00 00 03 73 03 01 01 05  |...s....|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 02 01 01 01 01 07 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 53 43 4F 54 54 01 0D 0D  |SCOTT...|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 41 55 54 48 5F 50 41 53  |AUTH_PAS|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 53 57 4F 52 44 01 08 08  |SWORD...|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 44 45 41 44 42 45 45 46  |DEADBEEF|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 00 01 0C 0C 41 55 54 48  |....AUTH|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 5F 53 45 53 53 4B 45 59  |_SESSKEY|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 01 08 08 43 41 46 45 42  |...CAFEB|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 41 42 45 01 01 01 0D 0D  |ABE.....|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 41 55 54 48 5F 54 45 52  |AUTH_TER|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 4D 49 4E 41 4C 01 05 05  |MINAL...|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 50 43 2D 34 32 00 01 0F  |PC-42...|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 0F 41 55 54 48 5F 50 52  |.AUTH_PR|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 4F 47 52 41 4D 5F 4E 4D  |OGRAM_NM|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 01 0B 0B 73 71 6C 70 6C  |...sqlpl|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 75 73 2E 65 78 65 00 01  |us.exe..|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 0C 0C 41 55 54 48 5F 4D  |..AUTH_M|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 41 43 48 49 4E 45 01 0F  |ACHINE..|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 0F 57 4F 52 4B 47 52 4F  |.WORKGRO|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 55 50 5C 50 43 2D 34 32  |UP\PC-42|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 00 01 08 08 41 55 54 48  |....AUTH|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 5F 50 49 44 01 09 09 35  |_PID...5|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 32 33 36 3A 35 32 34 30  |236:5240|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 00 01 08 08 41 55 54 48  |....AUTH|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 5F 53 49 44 01 04 04 6A  |_SID...j|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 64 6F 65 00              |doe.    |
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:746] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:746] nttfprd: socket 1288 had bytes read=134
+(5236) [22-OCT-2020 12:44:14:746] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 00 86 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 00 00 08 01 04 01 13 13  |........|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 41 55 54 48 5F 56 45 52  |AUTH_VER|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 53 49 4F 4E 5F 53 54 52  |SION_STR|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 49 4E 47 01 0C 0C 2D 20  |ING...-.|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 50 72 6F 64 75 63 74 69  |Producti|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 6F 6E 00 01 0F 0F 41 55  |on....AU|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 54 48 5F 53 45 53 53 49  |TH_SESSI|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 4F 4E 5F 49 44 01 03 03  |ON_ID...|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 31 33 36 00 01 0F 0F 41  |136....A|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 55 54 48 5F 53 45 52 49  |UTH_SERI|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 41 4C 5F 4E 55 4D 01 04  |AL_NUM..|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 04 32 38 31 37 00 01 11  |.2817...|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 11 41 55 54 48 5F 49 4E  |.AUTH_IN|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 53 54 41 4E 43 45 4E 41  |STANCENA|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 4D 45 01 04 04 6F 72 63  |ME...orc|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 6C 00 09 00 01 04        |l.....  |
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: exit: oln=0, dln=124, tot=134, rc=0
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=51
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 33 00 00 06 00 00 00  |.3......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 08 01 03 00 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 04 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 02 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 09 00 00                 |...     |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=41, tot=51, rc=0
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	if len(got) != 1 || got[0].Session == nil {
+		t.Errorf("Number of queries = %d, want 1", len(got))
+		return
+	}
+	s := got[0].Session
+	want := packet.Login{
+		User:      "SCOTT",
+		OSUser:    "jdoe",
+		Terminal:  "PC-42",
+		Program:   "sqlplus.exe",
+		Machine:   `WORKGROUP\PC-42`,
+		ClientPid: "5236:5240",
+		SessionId: "136",
+		SerialNum: "2817",
+		Instance:  "orcl",
+	}
+	if s.Login != want {
+		t.Errorf("Login = %+v, want %+v", s.Login, want)
+	}
+	if !strings.Contains(s.String(), "User(SCOTT)") {
+		t.Errorf("String() = %s", s.String())
+	}
+	if want := "nsbasic_bsd: User(SCOTT)\n"; !strings.Contains(got[0].String(), want) {
+		t.Errorf("Query String() = %q, want %q in the context line", got[0].String(), want)
+	}
+}
+
+func Test_loginRefused(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:14:740] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:740] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:14:740] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 00 00 03 76 02 01 01 05  |...v....|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 01 01 01 01 05 01 01 05  |........|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 53 43 4F 54 54 01 0D 0D  |SCOTT...|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 41 55 54 48 5F 54 45 52  |AUTH_TER|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 4D 49 4E 41 4C 01 05 05  |MINAL...|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 50 43 2D 34 32 00 01 0F  |PC-42...|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 0F 41 55 54 48 5F 50 52  |.AUTH_PR|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 4F 47 52 41 4D 5F 4E 4D  |OGRAM_NM|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 01 0B 0B 73 71 6C 70 6C  |...sqlpl|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 75 73 2E 65 78 65 00 01  |us.exe..|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 0C 0C 41 55 54 48 5F 4D  |..AUTH_M|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 41 43 48 49 4E 45 01 0F  |ACHINE..|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 0F 57 4F 52 4B 47 52 4F  |.WORKGRO|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 55 50 5C 50 43 2D 34 32  |UP\PC-42|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 00 01 08 08 41 55 54 48  |....AUTH|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 5F 50 49 44 01 09 09 35  |_PID...5|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 32 33 36 3A 35 32 34 30  |236:5240|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 00 01 08 08 41 55 54 48  |....AUTH|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 5F 53 49 44 01 04 04 6A  |_SID...j|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 64 6F 65 00              |doe.    |
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:742] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:742] nttfprd: socket 1288 had bytes read=79
+(5236) [22-OCT-2020 12:44:14:742] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 00 4F 00 00 06 00 00 00  |.O......|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 00 00 08 01 02 01 0C 0C  |........|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 41 55 54 48 5F 53 45 53  |AUTH_SES|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 53 4B 45 59 01 0C 0C 30  |SKEY...0|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 41 31 42 32 43 33 44 34  |A1B2C3D4|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 45 35 46 01 01 01 0D 0D  |E5F.....|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 41 55 54 48 5F 56 46 52  |AUTH_VFR|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 5F 44 41 54 41 01 08 08  |_DATA...|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 46 30 30 44 46 30 30 44  |F00DF00D|
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: 02 1B 25 09 00 01 03     |..%.... |
+(5236) [22-OCT-2020 12:44:14:742] nsbasic_brc: exit: oln=0, dln=69, tot=79, rc=0
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: tot=0, plen=220.
+(5236) [22-OCT-2020 12:44:14:744] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:744] nttfpwr: socket 1288 had bytes written=220
+(5236) [22-OCT-2020 12:44:14:744] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 00 DC 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 00 00 03 73 03 01 01 05  |...s....|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 02 01 01 01 01 07 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 53 43 4F 54 54 01 0D 0D  |SCOTT...|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 41 55 54 48 5F 50 41 53  |AUTH_PAS|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 53 57 4F 52 44 01 08 08  |SWORD...|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 44 45 41 44 42 45 45 46  |DEADBEEF|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 00 01 0C 0C 41 55 54 48  |....AUTH|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 5F 53 45 53 53 4B 45 59  |_SESSKEY|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 01 08 08 43 41 46 45 42  |...CAFEB|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 41 42 45 01 01 01 0D 0D  |ABE.....|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 41 55 54 48 5F 54 45 52  |AUTH_TER|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 4D 49 4E 41 4C 01 05 05  |MINAL...|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 50 43 2D 34 32 00 01 0F  |PC-42...|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 0F 41 55 54 48 5F 50 52  |.AUTH_PR|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 4F 47 52 41 4D 5F 4E 4D  |OGRAM_NM|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 01 0B 0B 73 71 6C 70 6C  |...sqlpl|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 75 73 2E 65 78 65 00 01  |us.exe..|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 0C 0C 41 55 54 48 5F 4D  |..AUTH_M|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 41 43 48 49 4E 45 01 0F  |ACHINE..|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 0F 57 4F 52 4B 47 52 4F  |.WORKGRO|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 55 50 5C 50 43 2D 34 32  |UP\PC-42|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 00 01 08 08 41 55 54 48  |....AUTH|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 5F 50 49 44 01 09 09 35  |_PID...5|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 32 33 36 3A 35 32 34 30  |236:5240|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 00 01 08 08 41 55 54 48  |....AUTH|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 5F 53 49 44 01 04 04 6A  |_SID...j|
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: 64 6F 65 00              |doe.    |
+(5236) [22-OCT-2020 12:44:14:744] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:746] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:746] nttfprd: socket 1288 had bytes read=96
+(5236) [22-OCT-2020 12:44:14:746] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 00 60 00 00 06 00 00 00  |.` + "`" + `......|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 00 00 04 00 02 03 F9 00  |........|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 00 33 4F 52 41 2D 30 31  |.3ORA-01|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 30 31 37 3A 20 69 6E 76  |017:.inv|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 61 6C 69 64 20 75 73 65  |alid.use|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 72 6E 61 6D 65 2F 70 61  |rname/pa|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 73 73 77 6F 72 64 3B 20  |ssword;.|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 6C 6F 67 6F 6E 20 64 65  |logon.de|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: 6E 69 65 64 0A 09 00 00  |nied....|
+(5236) [22-OCT-2020 12:44:14:746] nsbasic_brc: exit: oln=0, dln=86, tot=96, rc=0
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	if len(got) != 1 || got[0].Session == nil {
+		t.Errorf("Number of queries = %d, want 1", len(got))
+		return
+	}
+	if got[0].Event != "login refused for SCOTT" {
+		t.Errorf("Event = %q, want %q", got[0].Event, "login refused for SCOTT")
+	}
+	if got[0].Err == nil || got[0].Err.Code != 1017 || got[0].Session.LoginErr != got[0].Err {
+		t.Errorf("Err = %v, want ORA-01017", got[0].Err)
+	}
+	if want := "  => ORA-01017: invalid username/password; logon denied\n"; !strings.HasSuffix(got[0].String(), want) {
+		t.Errorf("String() = %q, want suffix %q", got[0].String(), want)
+	}
+}
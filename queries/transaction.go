@@ -0,0 +1,156 @@
+package queries
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/simulot/oracle_trc/packet"
+)
+
+// Outcome tells how a transaction ended
+type Outcome int
+
+const (
+	Open       Outcome = iota // Not ended in the trace
+	Committed                 // Ended by a commit, a commit on success or a DDL statement
+	RolledBack                // Ended by a rollback or a failed commit
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Committed:
+		return "committed"
+	case RolledBack:
+		return "rolled back"
+	}
+	return "not ended in the trace"
+}
+
+// Transaction groups the statements of a session up to the commit or the rollback that ends them
+type Transaction struct {
+	Id      int      // Transaction number in the session
+	Session *Session // Session of the transaction
+	Start   []byte   // Time stamp of the first call
+	End     []byte   // Time stamp of the call that ended the transaction
+	Queries []*Query // Calls in trace order, the commit or the rollback included
+	Outcome Outcome
+	XID     *packet.XID // Branch of the global transaction, nil for local transactions
+}
+
+// String gives transaction's context on one line
+func (t Transaction) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("Transaction #%d", t.Id))
+	if t.Session != nil {
+		sb.WriteString(fmt.Sprintf(" of session #%d", t.Session.Id))
+	}
+	sb.WriteString(", ")
+	sb.Write(t.Start)
+	if len(t.End) > 0 {
+		sb.WriteString(" - ")
+		sb.Write(t.End)
+	}
+	if t.XID != nil {
+		sb.WriteString(", XID ")
+		sb.WriteString(t.XID.String())
+	}
+	sb.WriteString(fmt.Sprintf(", %s, %d call(s)", t.Outcome, len(t.Queries)))
+	return sb.String()
+}
+
+// track adds the call to the session's current transaction, a new transaction is started when needed
+func (s *Session) track(q *Query) {
+	if q.Function == 0 {
+		// Connection event
+		return
+	}
+	t := s.tx
+	if t != nil && !sameXID(t.XID, q.XID) {
+		// The session is attached to another branch, or detached
+		t = nil
+	}
+	if t == nil {
+		t = &Transaction{
+			Id:      len(s.Transactions) + 1,
+			Session: s,
+			Start:   q.Packet.TS,
+			XID:     q.XID,
+		}
+		s.Transactions = append(s.Transactions, t)
+		s.tx = t
+	}
+	t.Queries = append(t.Queries, q)
+	q.Transaction = t
+	if o := q.outcome(); o != Open {
+		t.Outcome = o
+		t.End = q.Packet.TS
+		s.tx = nil
+	}
+}
+
+// ddlKeyWords starts statements that commit implicitly
+var ddlKeyWords = map[string]bool{
+	"CREATE":    true,
+	"ALTER":     true,
+	"DROP":      true,
+	"TRUNCATE":  true,
+	"RENAME":    true,
+	"GRANT":     true,
+	"REVOKE":    true,
+	"COMMENT":   true,
+	"ANALYZE":   true,
+	"AUDIT":     true,
+	"NOAUDIT":   true,
+	"PURGE":     true,
+	"FLASHBACK": true,
+}
+
+// outcome tells if the call ends the transaction
+func (q *Query) outcome() Outcome {
+	switch q.Function {
+	case packet.OCOMMIT:
+		if q.Err != nil {
+			return RolledBack
+		}
+		return Committed
+	case packet.OROLLBACK:
+		return RolledBack
+	case packet.OTXEN:
+		switch {
+		case q.XA == nil || q.Err != nil:
+			// A failed XA commit leaves the branch in doubt
+		case q.XA.Operation == "commit":
+			return Committed
+		case q.XA.Operation == "rollback":
+			return RolledBack
+		}
+		return Open
+	}
+	if q.Err != nil {
+		// Only the failed statement is rolled back
+		return Open
+	}
+	words := strings.Fields(string(toUpperAscii([]byte(q.Query))))
+	switch {
+	case len(words) == 0:
+	case words[0] == "COMMIT":
+		return Committed
+	case words[0] == "ROLLBACK":
+		if len(words) > 1 && words[1] == "TO" {
+			// Rollback to a savepoint
+			return Open
+		}
+		return RolledBack
+	case ddlKeyWords[words[0]]:
+		return Committed
+	}
+	if q.CommitOnSuccess() {
+		return Committed
+	}
+	return Open
+}
+
+// CommitOnSuccess checks if the client asked the server to commit when the statement succeeds
+func (q Query) CommitOnSuccess() bool {
+	return q.ExeOp&exeOpCommit != 0
+}
@@ -0,0 +1,115 @@
+package queries
+
+import (
+	"strings"
+
+	"github.com/simulot/oracle_trc/packet"
+)
+
+// XACall is a call of the XA interface on a branch of a global transaction
+type XACall struct {
+	Operation string      // start, end, prepare, commit, rollback or forget
+	XID       *packet.XID // Branch of the call
+	Flags     []string    // Options of start and end, like join, resume or suspend
+	State     string      // State of the branch given by the server, like read only after a prepare
+}
+
+var xaStartFlags = []struct {
+	flag uint32
+	name string
+}{
+	{packet.TxnNew, "new"},
+	{packet.TxnJoin, "join"},
+	{packet.TxnResume, "resume"},
+	{packet.TxnPromote, "promote"},
+}
+
+// newXACall gives the XA call of OTXSE and OTXEN calls, nil for other calls
+func newXACall(c packet.Caller) *XACall {
+	switch c := c.(type) {
+	case packet.TxnSwitch:
+		x := &XACall{XID: c.XID}
+		switch c.Operation {
+		case packet.TxnStart:
+			x.Operation = "start"
+			for _, f := range xaStartFlags {
+				if c.Flags&f.flag != 0 {
+					x.Flags = append(x.Flags, f.name)
+				}
+			}
+		case packet.TxnDetach:
+			x.Operation = "end"
+			if c.Flags&packet.TxnSuspend != 0 {
+				x.Flags = append(x.Flags, "suspend")
+			}
+		default:
+			return nil
+		}
+		return x
+	case packet.TxnChangeState:
+		x := &XACall{XID: c.XID}
+		switch c.Operation {
+		case packet.TxnCommit:
+			x.Operation = "commit"
+		case packet.TxnAbort:
+			x.Operation = "rollback"
+		case packet.TxnPrepare:
+			x.Operation = "prepare"
+		case packet.TxnForget:
+			x.Operation = "forget"
+		default:
+			return nil
+		}
+		return x
+	}
+	return nil
+}
+
+// received completes the call with the server's response
+func (x *XACall) received(r *Response) {
+	if r.XAState != "" {
+		x.State = r.XAState
+	}
+}
+
+// String describes the call, like xa_prepare 1.0A0B.01: read only
+func (x XACall) String() string {
+	sb := strings.Builder{}
+	sb.WriteString("xa_")
+	sb.WriteString(x.Operation)
+	if x.XID != nil {
+		sb.WriteString(" XID ")
+		sb.WriteString(x.XID.String())
+	}
+	if len(x.Flags) > 0 {
+		sb.WriteString(" (")
+		sb.WriteString(strings.Join(x.Flags, ", "))
+		sb.WriteString(")")
+	}
+	if x.State != "" {
+		sb.WriteString(": ")
+		sb.WriteString(x.State)
+	}
+	return sb.String()
+}
+
+// switchBranch attaches the session to the branch started by the call, or detaches it when the call ends the branch
+func (s *Session) switchBranch(q *Query) {
+	if q.XA == nil || q.Err != nil {
+		return
+	}
+	switch q.XA.Operation {
+	case "start":
+		s.XID = q.XA.XID
+	case "end":
+		s.XID = nil
+	}
+}
+
+// sameXID checks if both calls belong to the same branch, or are both outside XA
+func sameXID(a, b *packet.XID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.String() == b.String()
+}
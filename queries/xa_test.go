@@ -0,0 +1,233 @@
+package queries
+
+import (
+	"testing"
+
+	"github.com/simulot/oracle_trc/packet"
+)
+
+func Test_xaTransaction(t *testing.T) {
+	trc := `(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: tot=0, plen=41.
+(5236) [22-OCT-2020 12:44:14:740] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:740] nttfpwr: socket 1288 had bytes written=41
+(5236) [22-OCT-2020 12:44:14:740] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 00 29 00 00 06 00 00 00  |.)......|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 00 00 03 67 07 01 01 00  |...g....|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 00 01 01 01 02 01 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 01 03 01 01 01 3C 01 01  |.....<..|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 01 00 00 00 00 0A 0B 01  |........|
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: 00                       |.       |
+(5236) [22-OCT-2020 12:44:14:740] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:741] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:741] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:741] nttfprd: socket 1288 had bytes read=49
+(5236) [22-OCT-2020 12:44:14:741] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:741] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:741] nsbasic_brc: 00 31 00 00 06 00 00 00  |.1......|
+(5236) [22-OCT-2020 12:44:14:741] nsbasic_brc: 00 00 08 00 01 04 DE AD  |........|
+(5236) [22-OCT-2020 12:44:14:741] nsbasic_brc: BE EF 04 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:741] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:741] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:741] nsbasic_brc: 00 00 00 00 00 09 00 01  |........|
+(5236) [22-OCT-2020 12:44:14:741] nsbasic_brc: 05                       |.       |
+(5236) [22-OCT-2020 12:44:14:741] nsbasic_brc: exit: oln=0, dln=39, tot=49, rc=0
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: tot=0, plen=164.
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: socket 1288 had bytes written=164
+(5236) [22-OCT-2020 12:44:14:750] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 A4 00 00 06 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 11 69 15 01 01 01  |...i....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 02 03 5E 16 02 80 69  |...^...i|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 01 AE 01 01 0D 01  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 00 01 64 00 01 01 01  |...d....|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 01 00 01 01 01 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 01 00 00 00 00 00 3A  |.......:|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 53 45 4C 45 43 54 20 70  |SELECT.p|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 61 72 61 6D 5F 76 61 6C  |aram_val|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 75 65 20 46 52 4F 4D 20  |ue.FROM.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 65 66 6C 6F 77 5F 70 61  |eflow_pa|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 72 61 6D 73 20 57 48 45  |rams.WHE|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 52 45 20 70 61 72 61 6D  |RE.param|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 5F 6E 61 6D 65 20 3D 20  |_name.=.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 3A 31 01 01 00 00 00 00  |:1......|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 00 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 60 00 00 00 01 66 00  |.` + "`" + `....f.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 01 10 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 00 07 11 4C 49 43 45 58  |...LICEX|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 50 49 52 41 54 49 4F 4E  |PIRATION|
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: 44 41 54 45              |DATE    |
+(5236) [22-OCT-2020 12:44:14:750] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: socket 1288 had bytes read=116
+(5236) [22-OCT-2020 12:44:14:752] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 74 00 00 06 00 00 00  |.t......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 10 00 01 3B 01 03  |.....;..|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 02 00 0A 00 01 16 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 01 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 02 01 02 02 49 44 00 00  |....ID..|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 01 00 00 00 01 1E  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 02 03 69 01  |......i.|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 1E 00 01 04 01 04 04  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 4E 41 4D 45 00 00 00 00  |NAME....|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 0C 00 00 00 01 07 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 01 00 00 01 07  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 01 07 07 43 52 45 41 54  |...CREAT|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 45 44 00 00 00 00 00 00  |ED......|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 00 00 00 06 01 01 03  |........|
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: 00 01 01 00              |....    |
+(5236) [22-OCT-2020 12:44:14:752] nsbasic_brc: exit: oln=0, dln=106, tot=116, rc=0
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: socket 1288 had bytes read=117
+(5236) [22-OCT-2020 12:44:14:753] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 75 00 00 06 00 00 00  |.u......|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 00 00 07 02 C1 2B  |.......+|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 05 53 43 4F 54 54 07 78  |.SCOTT.x|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 78 0A 16 0D 2D 0F 15 01  |x...-...|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 02 05 07 02 C1 08 07 78  |.......x|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 78 0A 16 0D 2D 0F 07 02  |x...-...|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: C1 09 04 4B 49 4E 47 00  |...KING.|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 04 01 03 02 05 7B 00 00  |.....{..|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 01 02 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 00 00 00 00 00 00 00 19  |........|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 4F 52 41 2D 30 31 34 30  |ORA-0140|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 33 3A 20 6E 6F 20 64 61  |3:.no.da|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 74 61 20 66 6F 75 6E 64  |ta.found|
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: 0A 09 00 01 05           |.....   |
+(5236) [22-OCT-2020 12:44:14:753] nsbasic_brc: exit: oln=0, dln=107, tot=117, rc=0
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: tot=0, plen=37.
+(5236) [22-OCT-2020 12:44:14:770] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:770] nttfpwr: socket 1288 had bytes written=37
+(5236) [22-OCT-2020 12:44:14:770] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: 00 25 00 00 06 00 00 00  |.%......|
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: 00 00 03 67 08 01 02 01  |...g....|
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: 01 04 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: 00 01 01 01 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: DE AD BE EF 00           |.....   |
+(5236) [22-OCT-2020 12:44:14:770] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:771] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:771] nttfprd: socket 1288 had bytes read=44
+(5236) [22-OCT-2020 12:44:14:771] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 00 2C 00 00 06 00 00 00  |.,......|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 00 00 08 00 00 04 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: 09 00 01 05              |....    |
+(5236) [22-OCT-2020 12:44:14:771] nsbasic_brc: exit: oln=0, dln=34, tot=44, rc=0
+(5236) [22-OCT-2020 12:44:14:780] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:780] nsbasic_bsd: tot=0, plen=38.
+(5236) [22-OCT-2020 12:44:14:780] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:780] nttfpwr: socket 1288 had bytes written=38
+(5236) [22-OCT-2020 12:44:14:780] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:780] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:780] nsbasic_bsd: 00 26 00 00 06 00 00 00  |.&......|
+(5236) [22-OCT-2020 12:44:14:780] nsbasic_bsd: 00 00 03 68 09 01 03 01  |...h....|
+(5236) [22-OCT-2020 12:44:14:780] nsbasic_bsd: 01 04 01 01 01 02 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:780] nsbasic_bsd: 01 01 03 00 00 01 00 DE  |........|
+(5236) [22-OCT-2020 12:44:14:780] nsbasic_bsd: AD BE EF 0A 0B 01        |......  |
+(5236) [22-OCT-2020 12:44:14:780] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:781] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:781] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:781] nttfprd: socket 1288 had bytes read=44
+(5236) [22-OCT-2020 12:44:14:781] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:781] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:781] nsbasic_brc: 00 2C 00 00 06 00 00 00  |.,......|
+(5236) [22-OCT-2020 12:44:14:781] nsbasic_brc: 00 00 08 01 01 04 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:781] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:781] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:781] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:781] nsbasic_brc: 09 00 01 05              |....    |
+(5236) [22-OCT-2020 12:44:14:781] nsbasic_brc: exit: oln=0, dln=34, tot=44, rc=0
+(5236) [22-OCT-2020 12:44:14:790] nsbasic_bsd: entry
+(5236) [22-OCT-2020 12:44:14:790] nsbasic_bsd: tot=0, plen=39.
+(5236) [22-OCT-2020 12:44:14:790] nttfpwr: entry
+(5236) [22-OCT-2020 12:44:14:790] nttfpwr: socket 1288 had bytes written=39
+(5236) [22-OCT-2020 12:44:14:790] nttfpwr: exit
+(5236) [22-OCT-2020 12:44:14:790] nsbasic_bsd: packet dump
+(5236) [22-OCT-2020 12:44:14:790] nsbasic_bsd: 00 27 00 00 06 00 00 00  |.'......|
+(5236) [22-OCT-2020 12:44:14:790] nsbasic_bsd: 00 00 03 68 0A 01 01 01  |...h....|
+(5236) [22-OCT-2020 12:44:14:790] nsbasic_bsd: 01 04 01 01 01 02 01 01  |........|
+(5236) [22-OCT-2020 12:44:14:790] nsbasic_bsd: 01 01 03 00 01 02 01 00  |........|
+(5236) [22-OCT-2020 12:44:14:790] nsbasic_bsd: DE AD BE EF 0A 0B 01     |....... |
+(5236) [22-OCT-2020 12:44:14:790] nsbasic_bsd: exit (0)
+(5236) [22-OCT-2020 12:44:14:791] nsbasic_brc: entry: oln/tot=0
+(5236) [22-OCT-2020 12:44:14:791] nttfprd: entry
+(5236) [22-OCT-2020 12:44:14:791] nttfprd: socket 1288 had bytes read=44
+(5236) [22-OCT-2020 12:44:14:791] nttfprd: exit
+(5236) [22-OCT-2020 12:44:14:791] nsbasic_brc: packet dump
+(5236) [22-OCT-2020 12:44:14:791] nsbasic_brc: 00 2C 00 00 06 00 00 00  |.,......|
+(5236) [22-OCT-2020 12:44:14:791] nsbasic_brc: 00 00 08 01 02 04 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:791] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:791] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:791] nsbasic_brc: 00 00 00 00 00 00 00 00  |........|
+(5236) [22-OCT-2020 12:44:14:791] nsbasic_brc: 09 00 01 05              |....    |
+(5236) [22-OCT-2020 12:44:14:791] nsbasic_brc: exit: oln=0, dln=34, tot=44, rc=0
+`
+
+	got, err := getQueriesFromTraceSnippet(trc)
+	if err != nil {
+		t.Errorf("Error returned error = %v", err)
+		return
+	}
+	want := []string{
+		"xa_start XID 1.0A0B.01 (new)",
+		"",
+		"xa_end XID 1.0A0B.01",
+		"xa_prepare XID 1.0A0B.01: requires commit",
+		"xa_commit XID 1.0A0B.01: committed",
+	}
+	if len(got) != len(want) {
+		t.Errorf("Number of queries = %d, want %d", len(got), len(want))
+		return
+	}
+	for i, w := range want {
+		if got[i].Event != w || got[i].Err != nil {
+			t.Errorf("Query #%d event = %q, err = %v, want %q", i+1, got[i].Event, got[i].Err, w)
+		}
+		if got[i].XID == nil || got[i].XID.String() != "1.0A0B.01" {
+			t.Errorf("Query #%d XID = %v, want 1.0A0B.01", i+1, got[i].XID)
+		}
+	}
+	if s := got[0].Session; s.XID != nil || len(s.Transactions) != 1 {
+		t.Errorf("Session XID = %v, transactions = %d, want detached with 1 transaction", s.XID, len(s.Transactions))
+		return
+	}
+	tx := got[0].Transaction
+	wantTx := "Transaction #1 of session #1, 22-OCT-2020 12:44:14:740 - 22-OCT-2020 12:44:14:790, XID 1.0A0B.01, committed, 5 call(s)"
+	if tx.String() != wantTx {
+		t.Errorf("String() = %q, want %q", tx.String(), wantTx)
+	}
+}
+
+func TestQuery_xaOutcome(t *testing.T) {
+	tests := []struct {
+		name string
+		q    Query
+		want Outcome
+	}{
+		{name: "prepare", q: Query{Function: packet.OTXEN, XA: &XACall{Operation: "prepare"}}, want: Open},
+		{name: "commit", q: Query{Function: packet.OTXEN, XA: &XACall{Operation: "commit"}}, want: Committed},
+		{name: "failed commit", q: Query{Function: packet.OTXEN, XA: &XACall{Operation: "commit"}, Err: &OracleError{Code: 24756}}, want: Open},
+		{name: "rollback", q: Query{Function: packet.OTXEN, XA: &XACall{Operation: "rollback"}}, want: RolledBack},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.outcome(); got != tt.want {
+				t.Errorf("outcome() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
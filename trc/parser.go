@@ -2,6 +2,7 @@ package trc
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"runtime"
@@ -11,6 +12,9 @@ import (
 	"github.com/simulot/oracle_trc/packet"
 )
 
+// Disconnect is the type of the empty packet emitted when a socket is closed
+const Disconnect = "nttdisc"
+
 // Packet hold packet content and context of packet
 type Packet struct {
 	Name    string // Trace file name
@@ -20,6 +24,7 @@ type Packet struct {
 	Client  string // Client name
 	TS      []byte // Event time as written in trc file
 	Socket  int    // Socket
+	Version uint16 // Protocol version negotiated on the socket, 0 before the Accept packet
 	Payload []byte // Packet content
 }
 
@@ -30,6 +35,7 @@ type Parser struct {
 	buff            bytes.Buffer        // buffer used for gathering packet fragments
 	pkChan          chan packetAndError // gather extracted packets
 	clients         map[int]string      // Hold client names per PID
+	versions        map[int]uint16      // Protocol version negotiated per socket
 	packetType      string              // current packet type as seen in trc file
 	packetEndMarker []byte              // d
 	pk              *Packet             // current packet
@@ -47,6 +53,7 @@ func New(r io.Reader, name string) *Parser {
 		s:          newScanner(r),
 		pkChan:     make(chan packetAndError),
 		clients:    make(map[int]string),
+		versions:   make(map[int]uint16),
 		name:       name,
 		packetType: "",
 	}
@@ -96,6 +103,9 @@ func waitInterstingLines(p *Parser) stateFn {
 		if bytes.Contains(p.s.Bytes(), []byte("nsc2addr:")) {
 			return inNSC2Addr
 		}
+		if bytes.Contains(p.s.Bytes(), []byte("nttdisc: Closed socket")) {
+			return inDisconnect
+		}
 
 	}
 	p.EmitPacket(nil, p.s.Err())
@@ -134,10 +144,11 @@ func inPacket(p *Parser) stateFn {
 		}
 	}
 
-	var pk *Packet 
+	var pk *Packet
 	pk, p.pk = p.pk, nil
 	pk.Payload = make([]byte, p.buff.Len())
 	copy(pk.Payload, p.buff.Bytes())
+	p.setVersion(pk)
 	p.EmitPacket(pk, p.s.Err())
 	return waitInterstingLines
 }
@@ -162,6 +173,41 @@ func inDumpPacket(p *Parser) stateFn {
 	return inPacket // We need to parse all line regarding this frame
 }
 
+// inDisconnect emits an empty packet to signal the socket teardown
+func inDisconnect(p *Parser) stateFn {
+	b := p.s.Bytes()
+	pk := &Packet{
+		Name: p.name,
+		Line: p.s.Line,
+		Typ:  Disconnect,
+	}
+	pk.Pid, b = p.scanPID(b)
+	pk.Client = p.clients[pk.Pid]
+	pk.TS, _ = scanTS(b)
+
+	if i := bytes.Index(b, []byte("socket ")); i >= 0 {
+		pk.Socket, _ = strconv.Atoi(string(bytes.TrimSpace(b[i+len("socket "):])))
+	}
+	delete(p.versions, pk.Socket)
+	p.EmitPacket(pk, nil)
+	return waitInterstingLines
+}
+
+// setVersion follows the protocol version negotiated on the packet's socket.
+// Packets are stamped with the version in use, the Accept packet itself is not.
+func (p *Parser) setVersion(pk *Packet) {
+	if len(pk.Payload) >= 10 {
+		switch packet.PacketType(pk.Payload[4]) {
+		case packet.Connect:
+			delete(p.versions, pk.Socket)
+		case packet.Accept:
+			p.versions[pk.Socket] = binary.BigEndian.Uint16(pk.Payload[8:])
+			return
+		}
+	}
+	pk.Version = p.versions[pk.Socket]
+}
+
 // scanPID get PID from scanned line
 func (p *Parser) scanPID(b []byte) (int, []byte) {
 	pid := 0
@@ -185,15 +231,8 @@ func (p *Parser) scanPID(b []byte) (int, []byte) {
 	return pid, b
 }
 
-// scanPacketLine scan one of packets lines
-func (p *Parser) scanPacketLine(b []byte) {
-
-	if p.pk.Pid == 0 {
-		// Get PID and client
-		p.pk.Pid, b = p.scanPID(b)
-		p.pk.Client = p.clients[p.pk.Pid]
-	}
-
+// scanTS get the time stamp between brackets, and returns the remaining of the line starting at ']'
+func scanTS(b []byte) ([]byte, []byte) {
 	// Go to time stamp begin
 	i := 0
 	for i = 0; i < len(b); i++ {
@@ -203,21 +242,40 @@ func (p *Parser) scanPacketLine(b []byte) {
 		}
 	}
 
-	// Determine time stamp
 	b = b[i:]
 	for i = 0; i < len(b) && b[i] != ']'; i++ {
 	}
-	if len(p.pk.TS) == 0 && b[i] == ']' {
-		p.pk.TS = make([]byte, i)
-		copy(p.pk.TS, b[0:i])
+	if i >= len(b) {
+		return nil, nil
 	}
+	ts := make([]byte, i)
+	copy(ts, b[0:i])
+	return ts, b[i:]
+}
 
-	if i >= len(b) {
+// scanPacketLine scan one of packets lines
+func (p *Parser) scanPacketLine(b []byte) {
+
+	if p.pk.Pid == 0 {
+		// Get PID and client
+		p.pk.Pid, b = p.scanPID(b)
+		p.pk.Client = p.clients[p.pk.Pid]
+	}
+
+	// Determine time stamp
+	var ts []byte
+	ts, b = scanTS(b)
+	if len(p.pk.TS) == 0 {
+		p.pk.TS = ts
+	}
+
+	if len(b) == 0 {
 		return
 	}
+	i := 0
 
 	// skip packet type
-	b = b[i+2:]
+	b = b[2:]
 	for i = 0; i < len(b) && b[i] != ':'; i++ {
 	}
 
@@ -282,7 +340,10 @@ func (pk Packet) String() string {
 	sb := strings.Builder{}
 	pk.WriteContext(&sb)
 	writeEol(&sb)
-	pks := packet.PacketStringBuilder(pk.Payload)
+	if len(pk.Payload) < 8 {
+		return sb.String()
+	}
+	pks := packet.ReadPacket(pk.Payload, pk.Version)
 	pks.StringBuilder(&sb)
 	writeEol(&sb)
 	return sb.String()
@@ -91,6 +91,25 @@ func Test_getPacketFromTraceSnippet(t *testing.T) {
 				Socket:  1644,
 			},
 		},
+
+		{
+			name: "socket teardown",
+			args: args{
+				trc: "(2100) [11-MAR-2019 07:05:12:103] nsclose: entry\r\n" +
+					"(2100) [11-MAR-2019 07:05:12:103] nttdisc: entry\r\n" +
+					"(2100) [11-MAR-2019 07:05:12:104] nttdisc: Closed socket 1644\r\n" +
+					"(2100) [11-MAR-2019 07:05:12:104] nttdisc: exit\r\n" +
+					"",
+			},
+			want: &Packet{
+				Line:   3,
+				Pid:    2100,
+				TS:     []byte("11-MAR-2019 07:05:12:104"),
+				Typ:    Disconnect,
+				Client: "",
+				Socket: 1644,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -105,3 +124,61 @@ func Test_getPacketFromTraceSnippet(t *testing.T) {
 		})
 	}
 }
+
+func Test_versionPerSocket(t *testing.T) {
+	trc := "" +
+		"(2100) [11-MAR-2019 07:04:24:520] nsprecv: entry\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nsprecv: reading from transport...\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nttrd: entry\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nttrd: socket 1644 had bytes read=45\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nttrd: exit\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nsprecv: 45 bytes from transport\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nsprecv: tlen=45, plen=45, type=2\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nsprecv: packet dump\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nsprecv: 00 2D 00 00 02 00 00 00  |.-......|\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nsprecv: 01 3E 00 01 FF FF FF FF  |.>......|\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nsprecv: 01 00 00 00 00 2D 41 01  |.....-A.|\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nsprecv: 00 00 00 00 00 00 00 00  |........|\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nsprecv: 00 20 00 00 00 20 00 00  |........|\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nsprecv: 00 00 00 00 00           |.....   |\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:520] nsprecv: normal exit\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:529] nsbasic_bsd: entry\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:529] nsbasic_bsd: tot=0, plen=13.\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:529] nttfpwr: entry\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:529] nttfpwr: socket 1644 had bytes written=13\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:529] nttfpwr: exit\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:529] nsbasic_bsd: packet dump\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:529] nsbasic_bsd: 00 00 00 0D 06 00 00 00  |........|\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:529] nsbasic_bsd: 00 00 03 5E 01           |...^.   |\r\n" +
+		"(2100) [11-MAR-2019 07:04:24:529] nsbasic_bsd: exit (0)\r\n" +
+		"(2100) [11-MAR-2019 07:05:12:104] nttdisc: Closed socket 1644\r\n"
+
+	p := New(strings.NewReader(trc), "")
+	want := []struct {
+		typ     string
+		version uint16
+	}{
+		{"nsprecv", 0},
+		{"nsbasic_bsd", 318},
+		{Disconnect, 0},
+	}
+	for _, w := range want {
+		pk, err := p.NextPacket()
+		if err != nil || pk == nil {
+			t.Fatalf("NextPacket() = %v, %v, want a %s packet", pk, err, w.typ)
+		}
+		if pk.Typ != w.typ || pk.Version != w.version {
+			t.Errorf("NextPacket() = %s version %d, want %s version %d", pk.Typ, pk.Version, w.typ, w.version)
+		}
+	}
+}
+
+func TestPacket_StringTruncated(t *testing.T) {
+	pk := Packet{
+		Typ:     "nsbasic_bsd",
+		Payload: []byte{0x00, 0x0d, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03},
+	}
+	if got := pk.String(); !strings.Contains(got, "PkType(6=Data)") || !strings.Contains(got, "Function(03)") {
+		t.Errorf("String() = %q, want the fields of the truncated data packet", got)
+	}
+}